@@ -0,0 +1,144 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrNotAcknowledged indicates that a subscription was cancelled before acknowledging an event
+// passed to PublishAck
+var ErrNotAcknowledged = errors.New("A subscription was cancelled before acknowledging the event")
+
+// WithAck enables acknowledgement for a channel listener.  The consumer of the channel calls Ack on the
+// subscription once it has finished with each event, in the order events were received, and PublishAck waits
+// for those acknowledgements.  Events sent by other publish methods must be acknowledged as well, since
+// acknowledgements are matched to events by position in the channel.
+//
+// Sends to an acknowledged channel are serialized, so that positions match the order in which the
+// consumer receives events.  This option has no effect on other kinds of listeners, which have finished
+// with an event by the time they return.
+func WithAck() SubscribeOption {
+	return func(s *Subscription) {
+		if _, ok := s.sink.(*sinkChan); ok {
+			s.ack = new(ackState)
+		}
+	}
+}
+
+// ackState tracks the events sent to and acknowledged by an acknowledged channel listener.  events are
+// numbered from 1 in the order they were sent, so an event is acknowledged once acked reaches its number.
+type ackState struct {
+	// sendLock is held across each channel send, so that numbers match the order of the channel
+	sendLock sync.Mutex
+
+	lock      sync.Mutex
+	sent      uint64
+	acked     uint64
+	cancelled bool
+
+	// changed is closed and reset whenever acked or cancelled changes, waking any waiters.  it is
+	// guarded by lock and created lazily.
+	changed chan struct{}
+}
+
+// next numbers an event that has just been sent
+func (a *ackState) next() uint64 {
+	a.lock.Lock()
+	a.sent++
+	seq := a.sent
+	a.lock.Unlock()
+	return seq
+}
+
+// notify wakes any waiters.  it must be called while holding lock.
+func (a *ackState) notify() {
+	if a.changed != nil {
+		close(a.changed)
+		a.changed = nil
+	}
+}
+
+func (a *ackState) ack() {
+	a.lock.Lock()
+	if a.acked < a.sent {
+		a.acked++
+		a.notify()
+	}
+
+	a.lock.Unlock()
+}
+
+func (a *ackState) cancel() {
+	a.lock.Lock()
+	a.cancelled = true
+	a.notify()
+	a.lock.Unlock()
+}
+
+// wait blocks until the event with the given number is acknowledged, the subscription is cancelled,
+// or expired fires
+func (a *ackState) wait(seq uint64, expired <-chan time.Time) error {
+	for {
+		a.lock.Lock()
+		if a.acked >= seq {
+			a.lock.Unlock()
+			return nil
+		} else if a.cancelled {
+			a.lock.Unlock()
+			return ErrNotAcknowledged
+		}
+
+		if a.changed == nil {
+			a.changed = make(chan struct{})
+		}
+
+		changed := a.changed
+		a.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-expired:
+			return ErrTimeout
+		}
+	}
+}
+
+// pendingAck is an acknowledgement that PublishAck is waiting for
+type pendingAck struct {
+	ack *ackState
+	seq uint64
+}
+
+// expectAck records that an acknowledgement is due for the event with the given number, if
+// this message was published with PublishAck
+func (m message) expectAck(a *ackState, seq uint64) {
+	if m.acks != nil {
+		*m.acks = append(*m.acks, pendingAck{ack: a, seq: seq})
+	}
+}
+
+func (h *hub) PublishAck(e interface{}, timeout time.Duration) error {
+	var (
+		eventType = reflect.TypeOf(e)
+		pending   []pendingAck
+	)
+
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: reflect.ValueOf(e), acks: &pending}, nil)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for _, p := range pending {
+		if err := p.ack.wait(p.seq, timer.C); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package hub
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPublishAckAcknowledged(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h         = New()
+		events    = make(chan int, 10)
+		processed int32
+	)
+
+	sub, err := h.SubscribeWith(events, WithAck())
+	require.NoError(err)
+
+	// a listener without WithAck is never waited on
+	Must(h.Subscribe(make(chan int, 10)))
+
+	go func() {
+		for range events {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&processed, 1)
+			sub.Ack()
+		}
+	}()
+
+	// events from Publish are acknowledged in order along with the others
+	h.Publish(1)
+	assert.NoError(h.PublishAck(2, time.Minute))
+	assert.Equal(int32(2), atomic.LoadInt32(&processed))
+
+	// excess acknowledgements are ignored
+	sub.Ack()
+	assert.NoError(h.PublishAck(3, time.Minute))
+	assert.Equal(int32(3), atomic.LoadInt32(&processed))
+
+	sub.Cancel()
+	close(events)
+}
+
+func testPublishAckTimeout(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	sub, err := h.SubscribeWith(make(chan int, 2), WithAck())
+	require.NoError(err)
+
+	assert.Equal(ErrTimeout, h.PublishAck(1, 10*time.Millisecond))
+
+	// the event can still be acknowledged later
+	sub.Ack()
+	assert.Equal(ErrTimeout, h.PublishAck(2, 10*time.Millisecond))
+}
+
+func testPublishAckCancelled(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	sub, err := h.SubscribeWith(make(chan int, 1), WithAck())
+	require.NoError(err)
+
+	time.AfterFunc(10*time.Millisecond, func() { sub.Cancel() })
+	assert.Equal(ErrNotAcknowledged, h.PublishAck(1, time.Minute))
+}
+
+func testPublishAckNoListeners(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	assert.NoError(h.PublishAck(1, 0))
+
+	// WithAck has no effect on function listeners
+	var received []int
+	sub, err := h.SubscribeWith(func(e int) { received = append(received, e) }, WithAck())
+	require.NoError(err)
+	assert.Nil(sub.ack)
+	assert.NoError(h.PublishAck(2, 0))
+	assert.Equal([]int{2}, received)
+}
+
+func TestPublishAck(t *testing.T) {
+	t.Run("Acknowledged", testPublishAckAcknowledged)
+	t.Run("Timeout", testPublishAckTimeout)
+	t.Run("Cancelled", testPublishAckCancelled)
+	t.Run("NoListeners", testPublishAckNoListeners)
+}
@@ -0,0 +1,65 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrInvalidAlias indicates that Alias was passed types that cannot be aliased, i.e. a nil or interface type,
+// the same type twice, or a pair of types that do not share the same underlying type
+var ErrInvalidAlias = errors.New("An alias must be between distinct concrete types with the same underlying type")
+
+// loadAliases returns the current aliases, which may be nil
+func (h *hub) loadAliases() map[reflect.Type]reflect.Type {
+	aliases, _ := h.aliases.Load().(map[reflect.Type]reflect.Type)
+	return aliases
+}
+
+func (h *hub) Alias(from, to reflect.Type) error {
+	switch {
+	case from == nil || to == nil || from == to:
+		return ErrInvalidAlias
+
+	case from.Kind() == reflect.Interface || to.Kind() == reflect.Interface || !sameUnderlying(from, to):
+		return ErrInvalidAlias
+	}
+
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	// like the implementations registry, aliases are copy-on-write so that publishes need no lock
+	current := h.loadAliases()
+	updated := make(map[reflect.Type]reflect.Type, len(current)+1)
+	for k, v := range current {
+		updated[k] = v
+	}
+
+	updated[from] = to
+	h.aliases.Store(updated)
+	return nil
+}
+
+// sameUnderlying approximates a check that two types share the same underlying type.  reflect does not
+// expose underlying types, and ConvertibleTo alone also accepts value-changing conversions such as int to
+// string or int32 to float64.  requiring the same kind and size, and conversion in both directions, rules those out.
+func sameUnderlying(from, to reflect.Type) bool {
+	return from.Kind() == to.Kind() && from.Size() == to.Size() && from.ConvertibleTo(to) && to.ConvertibleTo(from)
+}
+
+// routeAlias delivers a message to the listeners of the type its event type is aliased to, if any.
+// the event is converted to that type first.  it returns true if the message matched at least one sink.
+func (h *hub) routeAlias(eventType reflect.Type, m message) bool {
+	to, ok := h.loadAliases()[eventType]
+	if !ok {
+		return false
+	}
+
+	aliased := h.subscriptions.sinks(to)
+	if len(aliased) == 0 {
+		return false
+	}
+
+	m.value = m.value.Convert(to)
+	h.deliver(m, aliased)
+	return true
+}
@@ -0,0 +1,60 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RenamedEvent has the same layout as TestEvent, as though TestEvent had been renamed
+type RenamedEvent TestEvent
+
+func TestAlias(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		oldType = reflect.TypeOf(TestEvent{})
+		newType = reflect.TypeOf(RenamedEvent{})
+
+		h = New()
+
+		oldEvents []TestEvent
+		newEvents []RenamedEvent
+		all       []interface{}
+	)
+
+	Must(h.Subscribe(func(e RenamedEvent) { newEvents = append(newEvents, e) }))
+	assert.False(h.PublishOK(TestEvent{Value: 1}))
+
+	require.NoError(h.Alias(oldType, newType))
+	assert.True(h.PublishOK(TestEvent{Value: 2}))
+	assert.Equal([]RenamedEvent{{Value: 2}}, newEvents)
+
+	// listeners of the old type, and catch-all listeners, still receive the original event once
+	Must(h.Subscribe(func(e TestEvent) { oldEvents = append(oldEvents, e) }))
+	Must(h.SubscribeAll(func(e interface{}) { all = append(all, e) }))
+	h.Publish(TestEvent{Value: 3})
+	assert.Equal([]TestEvent{{Value: 3}}, oldEvents)
+	assert.Equal([]RenamedEvent{{Value: 2}, {Value: 3}}, newEvents)
+	assert.Equal([]interface{}{TestEvent{Value: 3}}, all)
+
+	// aliases only apply in one direction
+	h.Publish(RenamedEvent{Value: 4})
+	assert.Equal([]TestEvent{{Value: 3}}, oldEvents)
+
+	for _, invalid := range [][2]reflect.Type{
+		{nil, newType},
+		{oldType, nil},
+		{oldType, oldType},
+		{oldType, reflect.TypeOf(0)},
+		{reflect.TypeOf(0), reflect.TypeOf("")},
+		{reflect.TypeOf(int32(0)), reflect.TypeOf(float64(0))},
+		{reflect.TypeOf(0), reflect.TypeOf(int64(0))},
+		{reflect.TypeOf((*error)(nil)).Elem(), newType},
+	} {
+		assert.Equal(ErrInvalidAlias, h.Alias(invalid[0], invalid[1]))
+	}
+}
@@ -0,0 +1,160 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// workerCount returns the number of worker goroutines this hub uses
+func (h *hub) workerCount() int {
+	if h.workers < 1 {
+		return 1
+	}
+
+	return h.workers
+}
+
+// startWorkers creates this hub's queue and the goroutines that service it
+func (h *hub) startWorkers() {
+	h.jobs = newQueue(h.queueCapacity, h.dropWhenFull)
+	for i := 0; i < h.workerCount(); i++ {
+		go h.work()
+	}
+}
+
+// stopWorkers closes this hub's queue, if one was started, and prevents one from starting later
+func (h *hub) stopWorkers() {
+	h.asyncOnce.Do(func() {})
+	if h.jobs != nil {
+		h.jobs.close()
+	}
+}
+
+// work is the loop executed by each worker goroutine
+func (h *hub) work() {
+	for {
+		j, ok := h.jobs.take()
+		if !ok {
+			return
+		}
+
+		if j.barrier != nil {
+			j.barrier.Done()
+			j.barrier.Wait()
+			continue
+		} else if j.probe != nil {
+			close(j.probe)
+			continue
+		}
+
+		if !j.enqueued.IsZero() {
+			h.observer.OnQueueLatency(time.Since(j.enqueued))
+		}
+
+		h.deliverAsync(j)
+		h.queueDepth(h.jobs.done(j))
+	}
+}
+
+// deliverAsync delivers a single job.  there is no caller to receive a panic, so any panic that escapes
+// delivery is recovered and logged rather than allowed to crash the process.
+func (h *hub) deliverAsync(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logf("asynchronous delivery of %v panicked: %v", j.eventType, r)
+		}
+	}()
+
+	h.dispatch(j.eventType, j.m, nil)
+}
+
+// queueDepth reports the number of pending asynchronous deliveries to the observer
+func (h *hub) queueDepth(depth int) {
+	if h.observer.OnQueueDepth != nil {
+		h.observer.OnQueueDepth(depth)
+	}
+}
+
+func (h *hub) PublishAsync(e interface{}) bool {
+	return h.publishAsync(e, false)
+}
+
+func (h *hub) PublishUrgent(e interface{}) bool {
+	return h.publishAsync(e, true)
+}
+
+// publishAsync enqueues an event for the workers at the given priority
+func (h *hub) publishAsync(e interface{}, urgent bool) bool {
+	h.asyncOnce.Do(h.startWorkers)
+	if h.jobs == nil {
+		// the hub was closed before any asynchronous publish
+		return false
+	}
+
+	var (
+		eventType = reflect.TypeOf(e)
+		m         = message{value: reflect.ValueOf(e)}
+		fixed     [2][]*Subscription
+	)
+
+	_, weight := h.route(fixed[:0], eventType, m)
+	if weight < 1 {
+		weight = 1
+	}
+
+	j := job{
+		eventType: eventType,
+		m:         m,
+		weight:    weight,
+		urgent:    urgent,
+	}
+
+	if h.observer.OnQueueLatency != nil {
+		j.enqueued = time.Now()
+	}
+
+	depth, ok := h.jobs.put(j)
+
+	if ok {
+		h.countPublish(eventType)
+	} else {
+		atomic.AddUint64(&h.counters.dropped, 1)
+	}
+
+	h.queueDepth(depth)
+	return ok
+}
+
+func (h *hub) Flush() {
+	h.asyncOnce.Do(h.startWorkers)
+	if h.jobs == nil {
+		return
+	}
+
+	h.flushLock.Lock()
+	defer h.flushLock.Unlock()
+
+	// every worker must take one sentinel before any of them can proceed.  since workers handle one
+	// job at a time and the queue is FIFO, all jobs enqueued before the sentinels are finished at that point.
+	var (
+		workers = h.workerCount()
+		barrier = new(sync.WaitGroup)
+	)
+
+	barrier.Add(workers)
+	for i := 0; i < workers; i++ {
+		if _, ok := h.jobs.put(job{barrier: barrier}); !ok {
+			// the queue was closed, so there is nothing left to wait for.  release any
+			// workers already parked on the sentinels that were accepted.
+			for ; i < workers; i++ {
+				barrier.Done()
+			}
+
+			return
+		}
+	}
+
+	barrier.Wait()
+}
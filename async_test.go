@@ -0,0 +1,371 @@
+package hub
+
+import (
+	"bytes"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPublishAsyncDelivery(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h        = New()
+		received = make(chan int, 3)
+	)
+
+	Must(h.Subscribe(received))
+	assert.True(h.PublishAsync(1))
+	assert.True(h.PublishAsync(2))
+	assert.True(h.PublishAsync(3))
+
+	// a single worker preserves order
+	assert.Equal(1, <-received)
+	assert.Equal(2, <-received)
+	assert.Equal(3, <-received)
+
+	h.Close()
+	assert.False(h.PublishAsync(4))
+}
+
+func testPublishAsyncWorkers(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		started = make(chan struct{}, 2)
+		gate    = make(chan struct{})
+		h       = New(WithWorkers(2))
+	)
+
+	Must(h.Subscribe(func(int) {
+		started <- struct{}{}
+		<-gate
+	}))
+
+	// both events are delivered concurrently
+	assert.True(h.PublishAsync(1))
+	assert.True(h.PublishAsync(2))
+	<-started
+	<-started
+	close(gate)
+	h.Close()
+}
+
+func testPublishAsyncClosedFirst(t *testing.T) {
+	h := New()
+	h.Close()
+	assert.False(t, h.PublishAsync(1))
+}
+
+func testPublishAsyncDrop(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		depths []int
+		lock   sync.Mutex
+		gate   = make(chan struct{})
+		done   = make(chan int, 10)
+
+		h = New(
+			WithGlobalQueue(2),
+			WithDropWhenFull(),
+			WithObserver(Observer{
+				OnQueueDepth: func(depth int) {
+					lock.Lock()
+					depths = append(depths, depth)
+					lock.Unlock()
+				},
+			}),
+		)
+	)
+
+	Must(h.Subscribe(func(e int) {
+		<-gate
+		done <- e
+	}))
+
+	Must(h.Subscribe(func(string) {}))
+
+	assert.True(h.PublishAsync(1))
+	assert.True(h.PublishAsync("a"))
+	assert.False(h.PublishAsync(2), "the queue should be full")
+
+	close(gate)
+	assert.Equal(1, <-done)
+
+	lock.Lock()
+	assert.Equal([]int{1, 2}, depths[:2])
+	lock.Unlock()
+}
+
+func testPublishAsyncBlock(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		gate     = make(chan struct{})
+		done     = make(chan int, 10)
+		returned = make(chan bool)
+
+		h = New(WithGlobalQueue(1))
+	)
+
+	Must(h.Subscribe(func(e int) {
+		<-gate
+		done <- e
+	}))
+
+	assert.True(h.PublishAsync(1))
+	go func() {
+		returned <- h.PublishAsync(2)
+	}()
+
+	select {
+	case <-returned:
+		assert.Fail("PublishAsync should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	assert.True(<-returned)
+	assert.Equal(1, <-done)
+	assert.Equal(2, <-done)
+}
+
+func testPublishAsyncPanic(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		output   bytes.Buffer
+		lock     sync.Mutex
+		received = make(chan string, 1)
+		h        = New(WithLogger(log.New(&lockedWriter{w: &output, lock: &lock}, "", 0)))
+	)
+
+	Must(h.Subscribe(func(int) { panic("expected") }))
+	Must(h.Subscribe(received))
+
+	assert.True(h.PublishAsync(1))
+	assert.True(h.PublishAsync("after panic"))
+	assert.Equal("after panic", <-received)
+
+	lock.Lock()
+	assert.Contains(output.String(), "expected")
+	lock.Unlock()
+}
+
+func testPublishAsyncFlush(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		count int32
+		h     = New(WithWorkers(4))
+	)
+
+	Must(h.Subscribe(func(int) {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&count, 1)
+	}))
+
+	for i := 0; i < 20; i++ {
+		assert.True(h.PublishAsync(i))
+	}
+
+	h.Flush()
+	assert.Equal(int32(20), atomic.LoadInt32(&count))
+
+	// flushing an idle hub returns immediately
+	h.Flush()
+	assert.Equal(int32(20), atomic.LoadInt32(&count))
+
+	h.Close()
+	h.Flush()
+}
+
+func testPublishAsyncFlushConcurrent(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		count int32
+		h     = New(WithWorkers(4))
+		done  = make(chan struct{})
+	)
+
+	Must(h.Subscribe(func(int) { atomic.AddInt32(&count, 1) }))
+
+	// several flushes run at once with several workers.  if the sentinels of different flushes interleaved
+	// in the queue, each worker could park on a different barrier, and no flush would ever return.
+	go func() {
+		defer close(done)
+		for round := 0; round < 20; round++ {
+			var (
+				wg    sync.WaitGroup
+				start = make(chan struct{})
+			)
+
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					h.PublishAsync(i)
+					h.Flush()
+				}(i)
+			}
+
+			close(start)
+			wg.Wait()
+		}
+	}()
+
+	select {
+	case <-done:
+		assert.Equal(int32(160), atomic.LoadInt32(&count))
+	case <-time.After(10 * time.Second):
+		assert.Fail("concurrent flushes deadlocked")
+	}
+
+	h.Close()
+}
+
+func testPublishAsyncFlushNotStarted(t *testing.T) {
+	h := New()
+	h.Flush()
+	h.Close()
+	h.Flush()
+}
+
+func testPublishAsyncUrgent(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		gate     = make(chan struct{})
+		started  = make(chan struct{})
+		received []interface{}
+
+		h = New()
+	)
+
+	Must(h.Subscribe(func(e int) {
+		if e == 0 {
+			close(started)
+			<-gate
+		}
+
+		received = append(received, e)
+	}))
+
+	Must(h.Subscribe(func(e string) {
+		received = append(received, e)
+	}))
+
+	// the first event occupies the single worker while a backlog builds
+	assert.True(h.PublishAsync(0))
+	<-started
+
+	assert.True(h.PublishAsync(1))
+	assert.True(h.PublishAsync(2))
+	assert.True(h.PublishUrgent("alarm"))
+	assert.True(h.PublishUrgent("shutdown"))
+
+	close(gate)
+	h.Flush()
+	assert.Equal([]interface{}{0, "alarm", "shutdown", 1, 2}, received)
+
+	h.Close()
+	assert.False(h.PublishUrgent("closed"))
+}
+
+func testPublishAsyncSelfCancel(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received []int
+		closed   = make(chan struct{})
+		h        = New(WithWorkers(4))
+	)
+
+	// a listener cancelling itself from a worker must not block against that worker or the others
+	Must(h.Subscribe(func(e int, cancel Cancel) {
+		received = append(received, e)
+		cancel()
+	}))
+
+	// nor must a listener that closes the hub
+	Must(h.Subscribe(func(e string) {
+		h.Close()
+		close(closed)
+	}))
+
+	assert.True(h.PublishAsync(1))
+	h.Flush()
+	assert.True(h.PublishAsync(2))
+	h.Flush()
+	assert.Equal([]int{1}, received)
+
+	assert.True(h.PublishAsync("close"))
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		assert.Fail("closing the hub from an asynchronous listener blocked")
+	}
+
+	assert.False(h.PublishAsync(3))
+}
+
+func testPublishAsyncQueueLatency(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		latencies []time.Duration
+		lock      sync.Mutex
+		gate      = make(chan struct{})
+
+		h = New(
+			WithObserver(Observer{
+				OnQueueLatency: func(d time.Duration) {
+					lock.Lock()
+					latencies = append(latencies, d)
+					lock.Unlock()
+				},
+			}),
+		)
+	)
+
+	Must(h.Subscribe(func(int) { <-gate }))
+
+	// the second event waits in the queue while the first is blocked
+	assert.True(h.PublishAsync(1))
+	assert.True(h.PublishAsync(2))
+	time.Sleep(20 * time.Millisecond)
+	close(gate)
+	h.Flush()
+
+	// synchronous publishes are never measured
+	h.Publish(3)
+
+	lock.Lock()
+	defer lock.Unlock()
+	if assert.Len(latencies, 2) {
+		assert.True(latencies[1] >= 20*time.Millisecond)
+	}
+}
+
+func TestPublishAsync(t *testing.T) {
+	t.Run("Delivery", testPublishAsyncDelivery)
+	t.Run("Workers", testPublishAsyncWorkers)
+	t.Run("ClosedFirst", testPublishAsyncClosedFirst)
+	t.Run("Drop", testPublishAsyncDrop)
+	t.Run("Block", testPublishAsyncBlock)
+	t.Run("Panic", testPublishAsyncPanic)
+	t.Run("Flush", testPublishAsyncFlush)
+	t.Run("FlushConcurrent", testPublishAsyncFlushConcurrent)
+	t.Run("FlushNotStarted", testPublishAsyncFlushNotStarted)
+	t.Run("Urgent", testPublishAsyncUrgent)
+	t.Run("SelfCancel", testPublishAsyncSelfCancel)
+	t.Run("QueueLatency", testPublishAsyncQueueLatency)
+}
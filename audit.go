@@ -0,0 +1,56 @@
+package hub
+
+import (
+	"reflect"
+	"time"
+)
+
+// AuditAction identifies the kind of change recorded by an AuditEntry
+type AuditAction int
+
+const (
+	// AuditSubscribe indicates that a subscription was added
+	AuditSubscribe AuditAction = iota
+
+	// AuditCancel indicates that a subscription was cancelled
+	AuditCancel
+)
+
+// String returns a human-readable name for this action
+func (aa AuditAction) String() string {
+	switch aa {
+	case AuditSubscribe:
+		return "subscribe"
+	case AuditCancel:
+		return "cancel"
+	default:
+		return "AuditAction(invalid)"
+	}
+}
+
+// AuditEntry is a structured record of a change to a hub's subscriptions
+type AuditEntry struct {
+	// Action is what happened to the subscription
+	Action AuditAction
+
+	// EventType is the subscription's event type.  For catch-all subscriptions, this is the empty interface.
+	EventType reflect.Type
+
+	// Label is the label supplied via WithLabel, if any
+	Label string
+
+	// Time is when the action occurred
+	Time time.Time
+}
+
+// audit records an action against a subscription, if this hub has an audit log
+func (h *hub) audit(action AuditAction, sub *Subscription) {
+	if h.auditLog != nil {
+		h.auditLog(AuditEntry{
+			Action:    action,
+			EventType: sub.eventType,
+			Label:     sub.label,
+			Time:      time.Now(),
+		})
+	}
+}
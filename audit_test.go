@@ -0,0 +1,54 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		entries []AuditEntry
+		h       = New(WithAuditLog(func(ae AuditEntry) { entries = append(entries, ae) }))
+	)
+
+	sub, err := h.SubscribeWith(func(int) {}, WithLabel("numbers"))
+	require.NoError(err)
+	assert.Equal("numbers", sub.Label())
+
+	_, err = h.Subscribe(func(string) {})
+	require.NoError(err)
+
+	sub.Cancel()
+	sub.Cancel()
+	h.Close()
+
+	require.Len(entries, 4)
+
+	assert.Equal(AuditSubscribe, entries[0].Action)
+	assert.Equal(reflect.TypeOf(0), entries[0].EventType)
+	assert.Equal("numbers", entries[0].Label)
+	assert.False(entries[0].Time.IsZero())
+
+	assert.Equal(AuditSubscribe, entries[1].Action)
+	assert.Equal(reflect.TypeOf(""), entries[1].EventType)
+	assert.Empty(entries[1].Label)
+
+	assert.Equal(AuditCancel, entries[2].Action)
+	assert.Equal("numbers", entries[2].Label)
+
+	assert.Equal(AuditCancel, entries[3].Action)
+	assert.Equal(reflect.TypeOf(""), entries[3].EventType)
+}
+
+func TestAuditActionString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("subscribe", AuditSubscribe.String())
+	assert.Equal("cancel", AuditCancel.String())
+	assert.Equal("AuditAction(invalid)", AuditAction(-1).String())
+}
@@ -0,0 +1,95 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+)
+
+// Barrier waits until at least one event of each of several types has been published.  It is useful for
+// startup coordination, where a component must wait for a number of distinct events, such as each dependency
+// announcing that it is ready, before proceeding.
+//
+// Each event type is a one-shot subscription made with WithLimit(1), so a Barrier holds no subscriptions
+// once it has opened.
+type Barrier struct {
+	remaining int32
+	done      chan struct{}
+	subs      []*Subscription
+}
+
+// NewBarrier subscribes to each of the given event types on h.  The returned Barrier opens once an event of every
+// type has been delivered.  A type that appears more than once must be delivered once for each appearance, which
+// a single publish accomplishes.  With no types, the Barrier is already open.
+//
+// The event types must be ones that h accepts for a function listener.  If any type cannot be subscribed, the
+// subscriptions already made are cancelled and the error is returned.
+func NewBarrier(h Dispatcher, types ...reflect.Type) (*Barrier, error) {
+	b := &Barrier{
+		remaining: int32(len(types)),
+		done:      make(chan struct{}),
+		subs:      make([]*Subscription, 0, len(types)),
+	}
+
+	if len(types) == 0 {
+		close(b.done)
+		return b, nil
+	}
+
+	for _, eventType := range types {
+		if eventType == nil {
+			b.Cancel()
+			return nil, ErrInvalidEventType
+		}
+
+		listener := reflect.MakeFunc(
+			reflect.FuncOf([]reflect.Type{eventType}, nil, false),
+			func([]reflect.Value) []reflect.Value {
+				b.arrive()
+				return nil
+			},
+		)
+
+		sub, err := h.SubscribeWith(listener.Interface(), WithLimit(1))
+		if err != nil {
+			b.Cancel()
+			return nil, err
+		}
+
+		b.subs = append(b.subs, sub)
+	}
+
+	return b, nil
+}
+
+// arrive records that one of this barrier's subscriptions has received its event
+func (b *Barrier) arrive() {
+	if atomic.AddInt32(&b.remaining, -1) == 0 {
+		close(b.done)
+	}
+}
+
+// Done returns a channel that is closed once this Barrier opens
+func (b *Barrier) Done() <-chan struct{} {
+	return b.done
+}
+
+// Wait blocks until this Barrier opens or ctx is done.  If ctx ends first, its error is returned and
+// the Barrier continues to wait for its events.
+func (b *Barrier) Wait(ctx context.Context) error {
+	select {
+	case <-b.done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Cancel abandons this Barrier, cancelling any subscriptions that have not yet fired.  A cancelled
+// Barrier that has not already opened never opens.
+func (b *Barrier) Cancel() {
+	for _, s := range b.subs {
+		s.Cancel()
+	}
+}
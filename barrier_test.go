@@ -0,0 +1,97 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBarrierWait(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	b, err := NewBarrier(h, reflect.TypeOf(0), reflect.TypeOf(""), reflect.TypeOf(TestEvent{}))
+	require.NoError(err)
+	require.NotNil(b)
+
+	h.Publish(1)
+	h.Publish(2)
+	h.Publish("one")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(context.DeadlineExceeded, b.Wait(ctx))
+
+	h.Publish(TestEvent{Value: 1})
+	assert.NoError(b.Wait(context.Background()))
+	assert.NoError(b.Wait(context.Background()))
+
+	select {
+	case <-b.Done():
+	default:
+		assert.Fail("The barrier did not open")
+	}
+
+	// each subscription has fired and cancelled itself
+	assert.Empty(h.Describe())
+}
+
+func testBarrierEmpty(t *testing.T) {
+	b, err := NewBarrier(New())
+	require.NoError(t, err)
+	assert.NoError(t, b.Wait(context.Background()))
+}
+
+func testBarrierInvalid(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h = New()
+	)
+
+	b, err := NewBarrier(h, reflect.TypeOf(0), nil)
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(b)
+
+	b, err = NewBarrier(h, reflect.TypeOf(0), reflect.TypeOf((*error)(nil)).Elem())
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(b)
+
+	// the subscriptions made before the failure were cancelled
+	assert.False(h.PublishOK(1))
+}
+
+func testBarrierCancel(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	b, err := NewBarrier(h, reflect.TypeOf(0), reflect.TypeOf(""))
+	require.NoError(err)
+
+	h.Publish(1)
+	b.Cancel()
+	assert.False(h.PublishOK("one"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(context.DeadlineExceeded, b.Wait(ctx))
+}
+
+func TestBarrier(t *testing.T) {
+	t.Run("Wait", testBarrierWait)
+	t.Run("Empty", testBarrierEmpty)
+	t.Run("Invalid", testBarrierInvalid)
+	t.Run("Cancel", testBarrierCancel)
+}
@@ -0,0 +1,167 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrInvalidBatchSize indicates that SubscribeBatch was passed a maximum batch size less than 1
+var ErrInvalidBatchSize = errors.New("The maximum batch size must be positive")
+
+// sinkBatch accumulates events and passes them to a listener in batches.  a single goroutine owns
+// the buffer and invokes the listener, so batches are delivered serially and in order.
+//
+// since the listener runs on that goroutine, cancellation never waits for it.  otherwise, a listener
+// that cancelled its own subscription would wait on itself.  instead, the goroutine flushes the final
+// batch and then runs the afterCancel closures itself.
+type sinkBatch struct {
+	hub         *hub
+	fn          reflect.Value
+	sliceType   reflect.Type
+	maxSize     int
+	maxWait     time.Duration
+	afterCancel []func()
+
+	events    chan reflect.Value
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// abandoned is set when the subscription was never registered, in which case the afterCancel
+	// closures do not run.  it is written before stop is closed, and read after.
+	abandoned bool
+}
+
+func (sb *sinkBatch) send(m message) {
+	select {
+	case sb.events <- m.value:
+	case <-sb.done:
+		// the batch was cancelled while this event was in flight
+	}
+}
+
+// run is the goroutine that accumulates events and flushes batches
+func (sb *sinkBatch) run() {
+	defer close(sb.done)
+	defer func() {
+		if !sb.abandoned {
+			for _, f := range sb.afterCancel {
+				f()
+			}
+		}
+	}()
+
+	var (
+		batch  = reflect.MakeSlice(sb.sliceType, 0, sb.maxSize)
+		timer  *time.Timer
+		timerC <-chan time.Time
+	)
+
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timerC = nil, nil
+		}
+
+		if batch.Len() > 0 {
+			sb.deliver(batch)
+			batch = reflect.MakeSlice(sb.sliceType, 0, sb.maxSize)
+		}
+	}
+
+	for {
+		select {
+		case v := <-sb.events:
+			batch = reflect.Append(batch, v)
+			if batch.Len() >= sb.maxSize {
+				flush()
+			} else if batch.Len() == 1 && sb.maxWait > 0 {
+				timer = time.NewTimer(sb.maxWait)
+				timerC = timer.C
+			}
+
+		case <-timerC:
+			timer, timerC = nil, nil
+			flush()
+
+		case <-sb.stop:
+			flush()
+			return
+		}
+	}
+}
+
+// deliver passes a batch to the listener.  there is no caller to receive a panic, so any panic
+// is recovered and logged.
+func (sb *sinkBatch) deliver(batch reflect.Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			sb.hub.logf("batch listener for %s panicked: %v", sb.sliceType, r)
+		}
+	}()
+
+	sb.fn.Call([]reflect.Value{batch})
+}
+
+// close stops the goroutine, which flushes any remaining events.  this method does not wait.
+func (sb *sinkBatch) close() {
+	sb.closeOnce.Do(func() {
+		close(sb.stop)
+	})
+}
+
+// abandon stops the goroutine for a subscription that was never registered
+func (sb *sinkBatch) abandon() {
+	sb.closeOnce.Do(func() {
+		sb.abandoned = true
+		close(sb.stop)
+	})
+}
+
+func (h *hub) SubscribeBatch(l interface{}, maxSize int, maxWait time.Duration, afterCancel ...func()) (Cancel, error) {
+	ft := reflect.TypeOf(l)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil, ErrInvalidListener
+	}
+
+	if ft.NumIn() != 1 || ft.NumOut() != 0 || ft.In(0).Kind() != reflect.Slice {
+		return nil, ErrInvalidFunction
+	}
+
+	if maxSize < 1 {
+		return nil, ErrInvalidBatchSize
+	}
+
+	eventType := ft.In(0).Elem()
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	sb := &sinkBatch{
+		hub:         h,
+		fn:          reflect.ValueOf(l),
+		sliceType:   ft.In(0),
+		maxSize:     maxSize,
+		maxWait:     maxWait,
+		afterCancel: afterCancel,
+		events:      make(chan reflect.Value),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go sb.run()
+
+	sub, err := h.register(eventType, sb, listenerKey(l), WithAfterCancel(sb.close))
+	if err != nil || sub.sink != sink(sb) {
+		// either registration failed, or an existing subscription was returned by WithDedup
+		sb.abandon()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.cancelFunc(), nil
+}
@@ -0,0 +1,144 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSubscribeBatchSize(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		batches     = make(chan []int, 10)
+		afterCancel = make(chan []int, 1)
+		h           = New()
+	)
+
+	cancel, err := h.SubscribeBatch(
+		func(b []int) { batches <- b },
+		3,
+		0,
+		func() { afterCancel <- nil },
+	)
+
+	require.NoError(err)
+
+	for i := 1; i <= 7; i++ {
+		h.Publish(i)
+	}
+
+	assert.Equal([]int{1, 2, 3}, <-batches)
+	assert.Equal([]int{4, 5, 6}, <-batches)
+
+	// cancellation flushes the partial batch before afterCancel
+	cancel()
+	assert.Equal([]int{7}, <-batches)
+	<-afterCancel
+
+	h.Publish(8)
+	assert.Empty(batches)
+}
+
+func testSubscribeBatchWait(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		batches = make(chan []string, 10)
+		h       = New()
+	)
+
+	_, err := h.SubscribeBatch(func(b []string) { batches <- b }, 100, 10*time.Millisecond)
+	require.NoError(err)
+
+	h.Publish("a")
+	h.Publish("b")
+	assert.Equal([]string{"a", "b"}, <-batches)
+
+	h.Publish("c")
+	assert.Equal([]string{"c"}, <-batches)
+
+	// closing the hub flushes as well
+	h.Publish("d")
+	h.Close()
+	assert.Equal([]string{"d"}, <-batches)
+}
+
+func testSubscribeBatchInvalid(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	for _, invalid := range []struct {
+		l        interface{}
+		maxSize  int
+		expected error
+	}{
+		{nil, 1, ErrInvalidListener},
+		{123, 1, ErrInvalidListener},
+		{func(int) {}, 1, ErrInvalidFunction},
+		{func([]int, int) {}, 1, ErrInvalidFunction},
+		{func([]int) error { return nil }, 1, ErrInvalidFunction},
+		{func([]int) {}, 0, ErrInvalidBatchSize},
+		{func([]interface{}) {}, 1, ErrInvalidEventType},
+	} {
+		cancel, err := h.SubscribeBatch(invalid.l, invalid.maxSize, time.Second)
+		assert.Equal(invalid.expected, err)
+		assert.Nil(cancel)
+	}
+
+	h.Close()
+	cancel, err := h.SubscribeBatch(func([]int) {}, 1, time.Second)
+	assert.Equal(ErrClosed, err)
+	assert.Nil(cancel)
+}
+
+func testSubscribeBatchSelfCancel(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		cancel      Cancel
+		batches     = make(chan []int, 10)
+		afterCancel = make(chan struct{})
+		h           = New()
+	)
+
+	// the listener cancels its own subscription from the batch goroutine
+	cancel, err := h.SubscribeBatch(
+		func(b []int) {
+			batches <- b
+			cancel()
+		},
+		2,
+		0,
+		func() { close(afterCancel) },
+	)
+
+	require.NoError(err)
+
+	h.PublishAsync(1)
+	h.PublishAsync(2)
+	h.Flush()
+
+	select {
+	case <-afterCancel:
+	case <-time.After(5 * time.Second):
+		assert.Fail("a batch listener cancelling itself blocked")
+	}
+
+	assert.Equal([]int{1, 2}, <-batches)
+	assert.False(h.PublishOK(3))
+}
+
+func TestSubscribeBatch(t *testing.T) {
+	t.Run("Size", testSubscribeBatchSize)
+	t.Run("Wait", testSubscribeBatchWait)
+	t.Run("Invalid", testSubscribeBatchInvalid)
+	t.Run("SelfCancel", testSubscribeBatchSelfCancel)
+}
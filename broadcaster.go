@@ -0,0 +1,78 @@
+package hub
+
+import "sync"
+
+// Broadcaster fans each published event out to a set of Publishers, such as the hubs of several modules.  It is
+// itself a Publisher, so it can be used wherever a single hub is expected.  Unlike Tee, which forwards from one
+// hub to another, a Broadcaster is used at the call site of Publish.
+//
+// A Broadcaster is safe for concurrent use.  Publishers may be added and removed at any time, including by
+// listeners of the events being broadcast.
+type Broadcaster struct {
+	lock       sync.RWMutex
+	publishers []Publisher
+}
+
+// NewBroadcaster creates a Broadcaster for the given publishers.  Nil publishers and duplicates are ignored,
+// exactly as with Add.
+func NewBroadcaster(publishers ...Publisher) *Broadcaster {
+	b := new(Broadcaster)
+	for _, p := range publishers {
+		b.Add(p)
+	}
+
+	return b
+}
+
+// Add includes a publisher in this Broadcaster, returning true if it was added.  A nil publisher, this Broadcaster
+// itself, or a publisher that is already included is not added.
+func (b *Broadcaster) Add(p Publisher) bool {
+	if p == nil || p == Publisher(b) {
+		return false
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, existing := range b.publishers {
+		if existing == p {
+			return false
+		}
+	}
+
+	// publishers is copy-on-write, so that Publish can use it after releasing the lock
+	updated := make([]Publisher, len(b.publishers), len(b.publishers)+1)
+	copy(updated, b.publishers)
+	b.publishers = append(updated, p)
+	return true
+}
+
+// Remove excludes a publisher from this Broadcaster, returning true if it was included
+func (b *Broadcaster) Remove(p Publisher) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for i, existing := range b.publishers {
+		if existing == p {
+			updated := make([]Publisher, 0, len(b.publishers)-1)
+			updated = append(updated, b.publishers[:i]...)
+			b.publishers = append(updated, b.publishers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Publish publishes an event to each publisher in this Broadcaster, in the order they were added.  The set of
+// publishers is captured when this method is called, so changes made during the broadcast take effect with the
+// next event.
+func (b *Broadcaster) Publish(e interface{}) {
+	b.lock.RLock()
+	publishers := b.publishers
+	b.lock.RUnlock()
+
+	for _, p := range publishers {
+		p.Publish(e)
+	}
+}
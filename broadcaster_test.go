@@ -0,0 +1,54 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcaster(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h1 = New()
+		h2 = New()
+		h3 = New()
+
+		first  []int
+		second []int
+		third  []int
+
+		b = NewBroadcaster(h1, nil, h2, h1)
+	)
+
+	Must(h1.Subscribe(func(e int) { first = append(first, e) }))
+	Must(h2.Subscribe(func(e int) { second = append(second, e) }))
+	Must(h3.Subscribe(func(e int) {
+		third = append(third, e)
+
+		// changes made while broadcasting apply to the next event
+		b.Remove(h3)
+	}))
+
+	var p Publisher = b
+	p.Publish(1)
+	assert.Equal([]int{1}, first)
+	assert.Equal([]int{1}, second)
+
+	assert.True(b.Add(h3))
+	assert.False(b.Add(h3))
+	assert.False(b.Add(b))
+	assert.False(b.Add(nil))
+	b.Publish(2)
+	b.Publish(3)
+	assert.Equal([]int{1, 2, 3}, first)
+	assert.Equal([]int{2}, third)
+
+	assert.True(b.Remove(h1))
+	assert.False(b.Remove(h1))
+	b.Publish(4)
+	assert.Equal([]int{1, 2, 3}, first)
+	assert.Equal([]int{1, 2, 3, 4}, second)
+
+	assert.NotPanics(func() { new(Broadcaster).Publish(5) })
+}
@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+package hub
+
+import (
+	"context"
+	"reflect"
+)
+
+// Bus is a strongly typed view of a hub that carries a single event type.  Several buses, each for a different
+// type, may share one hub, so that every module gets a discoverable Publish and Subscribe for its own events while
+// dispatch, options, and observers remain centralized:
+//
+//	created, err := hub.NewBus[UserCreated](h)
+//	deleted, err := hub.NewBus[UserDeleted](h)
+//
+//	created.Subscribe(func(e UserCreated) { ... })
+//	created.Publish(UserCreated{ID: id})
+//
+// Buses over the same hub never cross-talk, since a hub routes each event by its concrete type and a bus only
+// carries a single concrete type.  Buses for the same type over the same hub are interchangeable, and events
+// of that type published directly to the hub reach a bus's subscribers as well.
+type Bus[E any] struct {
+	hub Dispatcher
+}
+
+// NewBus creates a Bus for events of type E over the given hub.  E must be a concrete type, since a bus for an
+// interface type would publish events of many types and so could reach the subscribers of other buses.  If E is
+// an interface type, ErrInvalidEventType is returned.
+func NewBus[E any](h Dispatcher) (*Bus[E], error) {
+	if reflect.TypeOf((*E)(nil)).Elem().Kind() == reflect.Interface {
+		return nil, ErrInvalidEventType
+	}
+
+	return &Bus[E]{hub: h}, nil
+}
+
+// Hub returns the hub that this bus publishes to and subscribes with
+func (b *Bus[E]) Hub() Dispatcher {
+	return b.hub
+}
+
+// Publish publishes an event to the subscribers of this bus.  See Publisher.Publish.
+func (b *Bus[E]) Publish(e E) {
+	b.hub.Publish(e)
+}
+
+// PublishContext publishes an event along with a context.  See Dispatcher.PublishContext.
+func (b *Bus[E]) PublishContext(ctx context.Context, e E) {
+	b.hub.PublishContext(ctx, e)
+}
+
+// Subscribe registers a listener for this bus's events.  See Subscriber.Subscribe.
+func (b *Bus[E]) Subscribe(fn func(E), afterCancel ...func()) (Cancel, error) {
+	return b.hub.Subscribe(fn, afterCancel...)
+}
+
+// SubscribeWith registers a listener for this bus's events with the given options.  See Dispatcher.SubscribeWith.
+func (b *Bus[E]) SubscribeWith(fn func(E), options ...SubscribeOption) (*Subscription, error) {
+	return b.hub.SubscribeWith(fn, options...)
+}
+
+// SubscribeChan subscribes a new channel to this bus's events.  See the SubscribeChan function.
+func (b *Bus[E]) SubscribeChan(buffer int) (<-chan E, Cancel, error) {
+	return SubscribeChan[E](b.hub, buffer)
+}
@@ -0,0 +1,68 @@
+//go:build go1.21
+// +build go1.21
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+
+		events  []TestEvent
+		strs    []string
+		ctxSeen context.Context
+	)
+
+	eventBus, err := NewBus[TestEvent](h)
+	require.NoError(err)
+	require.NotNil(eventBus)
+	assert.Equal(h, eventBus.Hub())
+
+	strBus, err := NewBus[string](h)
+	require.NoError(err)
+
+	_, err = eventBus.Subscribe(func(e TestEvent) { events = append(events, e) })
+	require.NoError(err)
+
+	sub, err := strBus.SubscribeWith(func(e string) { strs = append(strs, e) }, WithLabel("strings"))
+	require.NoError(err)
+	assert.Equal("strings", sub.Label())
+
+	_, err = h.SubscribeContextHandler(func(ctx context.Context, e TestEvent) { ctxSeen = ctx })
+	require.NoError(err)
+
+	// buses sharing a hub do not cross-talk
+	eventBus.Publish(TestEvent{Value: 1})
+	strBus.Publish("one")
+	assert.Equal([]TestEvent{{Value: 1}}, events)
+	assert.Equal([]string{"one"}, strs)
+
+	ctx := context.WithValue(context.Background(), testSpanKey{}, "value")
+	eventBus.PublishContext(ctx, TestEvent{Value: 2})
+	assert.Equal(ctx, ctxSeen)
+
+	// events published directly to the hub reach the bus
+	h.Publish("two")
+	assert.Equal([]string{"one", "two"}, strs)
+
+	c, cancel, err := strBus.SubscribeChan(1)
+	require.NoError(err)
+	strBus.Publish("three")
+	assert.Equal("three", <-c)
+	cancel()
+
+	stringers, err := NewBus[fmt.Stringer](h)
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(stringers)
+}
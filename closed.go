@@ -0,0 +1,70 @@
+package hub
+
+import (
+	"errors"
+	"runtime"
+)
+
+// ErrClosedChannel indicates that an event could not be delivered to a channel listener because the
+// channel was closed while still subscribed
+var ErrClosedChannel = errors.New("The channel was closed before its subscription was cancelled")
+
+// ClosedChannelPolicy describes how a hub reacts when a channel listener's channel has been closed without
+// cancelling its subscription.  The policy is set with WithClosedChannelPolicy.
+type ClosedChannelPolicy int
+
+const (
+	// ClosedChannelPanic lets the send on the closed channel panic, as it would for any other send.  The panic is
+	// then handled by the hub's PanicPolicy like any other listener panic.  This is the default policy.
+	ClosedChannelPanic ClosedChannelPolicy = iota
+
+	// ClosedChannelRemove recovers the panic, drops the event, and cancels the channel's subscription.  Delivery
+	// continues with the remaining sinks, and the channel receives nothing further.
+	ClosedChannelRemove
+
+	// ClosedChannelRecover recovers the panic and drops the event, but leaves the subscription in place.  Every
+	// later event for the channel is dropped in the same way until the subscription is cancelled.
+	ClosedChannelRecover
+)
+
+// String returns a human-readable name for this policy
+func (ccp ClosedChannelPolicy) String() string {
+	switch ccp {
+	case ClosedChannelPanic:
+		return "ClosedChannelPanic"
+	case ClosedChannelRemove:
+		return "ClosedChannelRemove"
+	case ClosedChannelRecover:
+		return "ClosedChannelRecover"
+	default:
+		return "ClosedChannelPolicy(invalid)"
+	}
+}
+
+// isClosedChannelPanic tests if a recovered value came from sending on a closed channel
+func isClosedChannelPanic(r interface{}) bool {
+	err, ok := r.(runtime.Error)
+	return ok && err.Error() == "send on closed channel"
+}
+
+// sendChecked sends a message's event to the channel exactly as trySend does.  unless the hub's policy is
+// ClosedChannelPanic, a send on a closed channel is recovered and reported via closed.  any other panic
+// is passed on.
+func (sc *sinkChan) sendChecked(m message) (sent, closed bool) {
+	if m.sub.hub.closedChannelPolicy == ClosedChannelPanic {
+		return sc.trySend(m), false
+	}
+
+	closed = true
+	defer func() {
+		if closed {
+			if r := recover(); !isClosedChannelPanic(r) {
+				panic(r)
+			}
+		}
+	}()
+
+	sent = sc.trySend(m)
+	closed = false
+	return
+}
@@ -0,0 +1,84 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClosedChannelPanic(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h = New()
+		c = make(chan int, 1)
+	)
+
+	Must(h.Subscribe(c))
+	close(c)
+	assert.Panics(func() { h.Publish(1) })
+}
+
+func testClosedChannelRemove(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New(WithClosedChannelPolicy(ClosedChannelRemove))
+		closed   = make(chan int, 1)
+		open     = make(chan int, 2)
+		received []int
+	)
+
+	sub, err := h.SubscribeWith(closed, WithCloseOnCancel())
+	require.NoError(err)
+	Must(h.Subscribe(open))
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+
+	close(closed)
+	var report Report
+	assert.NotPanics(func() { report = h.PublishReport(1) })
+	assert.False(sub.Active())
+	require.NotEmpty(report.Results)
+	assert.Equal(Dropped, report.Results[0].Outcome)
+	assert.Equal(ErrClosedChannel, report.Results[0].Err)
+
+	h.Publish(2)
+	assert.Equal(1, <-open)
+	assert.Equal(2, <-open)
+	assert.Equal([]int{1, 2}, received)
+	assert.Equal(uint64(1), h.Stats().DroppedCount)
+}
+
+func testClosedChannelRecover(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New(WithClosedChannelPolicy(ClosedChannelRecover))
+		c = make(chan int, 1)
+	)
+
+	sub, err := h.SubscribeWith(c)
+	require.NoError(err)
+
+	close(c)
+	assert.NotPanics(func() { h.Publish(1) })
+	assert.NotPanics(func() { h.Publish(2) })
+	assert.True(sub.Active())
+	assert.Equal(uint64(2), h.Stats().DroppedCount)
+
+	// other panics are not recovered
+	Must(h.Subscribe(func(int) { panic("expected") }))
+	assert.PanicsWithValue("expected", func() { h.Publish(3) })
+}
+
+func TestClosedChannelPolicy(t *testing.T) {
+	t.Run("Panic", testClosedChannelPanic)
+	t.Run("Remove", testClosedChannelRemove)
+	t.Run("Recover", testClosedChannelRecover)
+
+	assert.Equal(t, "ClosedChannelRemove", ClosedChannelRemove.String())
+	assert.Equal(t, "ClosedChannelPolicy(invalid)", ClosedChannelPolicy(-1).String())
+}
@@ -0,0 +1,214 @@
+package hub
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrInvalidConfig indicates that a Config passed to NewFromConfig failed validation
+var ErrInvalidConfig = errors.New("The hub configuration is invalid")
+
+// Config is a declarative alternative to passing options to New.  Each field corresponds to an Option,
+// and the zero value of each field produces the same behavior as omitting that Option.  The simple fields
+// can be unmarshaled from a configuration file, while the function and pointer fields must be set in code.
+type Config struct {
+	// PanicPolicy corresponds to WithPanicPolicy
+	PanicPolicy PanicPolicy `json:"panicPolicy"`
+
+	// Dedup corresponds to WithDedup
+	Dedup bool `json:"dedup"`
+
+	// NoDuplicates corresponds to WithNoDuplicates
+	NoDuplicates bool `json:"noDuplicates"`
+
+	// DefensiveCopy corresponds to WithDefensiveCopy
+	DefensiveCopy bool `json:"defensiveCopy"`
+
+	// CopyDepth corresponds to WithCopyDepth.  Unlike the option, 0 leaves the default depth in place
+	// and a negative depth makes shallow copies.
+	CopyDepth int `json:"copyDepth"`
+
+	// SlowThreshold corresponds to WithSlowThreshold, and must not be negative
+	SlowThreshold time.Duration `json:"slowThreshold"`
+
+	// ChannelSendTimeout corresponds to WithChannelSendTimeout
+	ChannelSendTimeout time.Duration `json:"channelSendTimeout"`
+
+	// ClosedChannelPolicy corresponds to WithClosedChannelPolicy
+	ClosedChannelPolicy ClosedChannelPolicy `json:"closedChannelPolicy"`
+
+	// DeliveryOrder corresponds to WithDeliveryOrder
+	DeliveryOrder Order `json:"deliveryOrder"`
+
+	// ShutdownOrder corresponds to WithShutdownOrder
+	ShutdownOrder Order `json:"shutdownOrder"`
+
+	// Strategy corresponds to WithStrategy.  Per-type strategies must be set with WithTypeStrategy.
+	Strategy Strategy `json:"strategy"`
+
+	// Workers corresponds to WithWorkers, and must not be negative
+	Workers int `json:"workers"`
+
+	// QueueCapacity corresponds to WithGlobalQueue, and must not be negative
+	QueueCapacity int `json:"queueCapacity"`
+
+	// DropWhenFull corresponds to WithDropWhenFull
+	DropWhenFull bool `json:"dropWhenFull"`
+
+	// InitialBuckets corresponds to WithInitialBuckets, and must not be negative
+	InitialBuckets int `json:"initialBuckets"`
+
+	// PausePolicy corresponds to WithPausePolicy
+	PausePolicy PausePolicy `json:"pausePolicy"`
+
+	// PauseCapacity corresponds to WithPauseCapacity, and must not be negative
+	PauseCapacity int `json:"pauseCapacity"`
+
+	// RejectInvalid corresponds to WithRejectInvalid
+	RejectInvalid bool `json:"rejectInvalid"`
+
+	// UnregisteredPolicy corresponds to WithUnregisteredPolicy
+	UnregisteredPolicy UnregisteredPolicy `json:"unregisteredPolicy"`
+
+	// DrainTimeout corresponds to WithDrainTimeout, and must not be negative
+	DrainTimeout time.Duration `json:"drainTimeout"`
+
+	// SinkKindOrder corresponds to WithSinkKindOrder, and must contain only the SinkKind constants
+	SinkKindOrder []SinkKind `json:"sinkKindOrder"`
+
+	// Logger corresponds to WithLogger
+	Logger *log.Logger `json:"-"`
+
+	// Unhandled corresponds to WithUnhandled
+	Unhandled func(interface{}) `json:"-"`
+
+	// ErrorHandler corresponds to WithErrorHandler
+	ErrorHandler func(error) `json:"-"`
+
+	// ConsumerWaitGroup corresponds to WithConsumerWaitGroup
+	ConsumerWaitGroup *sync.WaitGroup `json:"-"`
+
+	// Observer corresponds to WithObserver
+	Observer Observer `json:"-"`
+
+	// AuditLog corresponds to WithAuditLog
+	AuditLog func(AuditEntry) `json:"-"`
+
+	// KeyFunc corresponds to WithKeyFunc
+	KeyFunc func(interface{}) interface{} `json:"-"`
+}
+
+// Validate checks this configuration, returning ErrInvalidConfig if any field is out of range
+func (c Config) Validate() error {
+	switch {
+	case c.PanicPolicy < Propagate || c.PanicPolicy > Repanic:
+		return ErrInvalidConfig
+
+	case c.ClosedChannelPolicy < ClosedChannelPanic || c.ClosedChannelPolicy > ClosedChannelRecover:
+		return ErrInvalidConfig
+
+	case c.DeliveryOrder < FIFO || c.DeliveryOrder > LIFO:
+		return ErrInvalidConfig
+
+	case c.ShutdownOrder < FIFO || c.ShutdownOrder > LIFO:
+		return ErrInvalidConfig
+
+	case c.Strategy < Sync || c.Strategy > Async:
+		return ErrInvalidConfig
+
+	case c.PausePolicy < PauseBuffer || c.PausePolicy > PauseDrop:
+		return ErrInvalidConfig
+
+	case c.UnregisteredPolicy < AllowUnregistered || c.UnregisteredPolicy > PanicUnregistered:
+		return ErrInvalidConfig
+
+	case c.SlowThreshold < 0 || c.Workers < 0 || c.QueueCapacity < 0 || c.InitialBuckets < 0:
+		return ErrInvalidConfig
+
+	case c.PauseCapacity < 0 || c.DrainTimeout < 0:
+		return ErrInvalidConfig
+
+	case !validSinkKinds(c.SinkKindOrder):
+		return ErrInvalidConfig
+
+	default:
+		return nil
+	}
+}
+
+// validSinkKinds tests that each of the given kinds is one of the SinkKind constants
+func validSinkKinds(kinds []SinkKind) bool {
+	for _, kind := range kinds {
+		if kind < FuncSink || kind > LazySink {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Options returns the options equivalent to this configuration.  No validation is performed.
+func (c Config) Options() []Option {
+	options := []Option{
+		WithPanicPolicy(c.PanicPolicy),
+		WithLogger(c.Logger),
+		WithUnhandled(c.Unhandled),
+		WithErrorHandler(c.ErrorHandler),
+		WithConsumerWaitGroup(c.ConsumerWaitGroup),
+		WithObserver(c.Observer),
+		WithSlowThreshold(c.SlowThreshold),
+		WithChannelSendTimeout(c.ChannelSendTimeout),
+		WithClosedChannelPolicy(c.ClosedChannelPolicy),
+		WithAuditLog(c.AuditLog),
+		WithKeyFunc(c.KeyFunc),
+		WithDeliveryOrder(c.DeliveryOrder),
+		WithShutdownOrder(c.ShutdownOrder),
+		WithStrategy(c.Strategy),
+		WithWorkers(c.Workers),
+		WithGlobalQueue(c.QueueCapacity),
+		WithInitialBuckets(c.InitialBuckets),
+		WithPausePolicy(c.PausePolicy),
+		WithPauseCapacity(c.PauseCapacity),
+		WithUnregisteredPolicy(c.UnregisteredPolicy),
+		WithDrainTimeout(c.DrainTimeout),
+		WithSinkKindOrder(c.SinkKindOrder),
+	}
+
+	if c.Dedup {
+		options = append(options, WithDedup())
+	}
+
+	if c.NoDuplicates {
+		options = append(options, WithNoDuplicates())
+	}
+
+	if c.CopyDepth != 0 {
+		options = append(options, WithCopyDepth(c.CopyDepth))
+	}
+
+	if c.DefensiveCopy {
+		options = append(options, WithDefensiveCopy())
+	}
+
+	if c.DropWhenFull {
+		options = append(options, WithDropWhenFull())
+	}
+
+	if c.RejectInvalid {
+		options = append(options, WithRejectInvalid())
+	}
+
+	return options
+}
+
+// NewFromConfig validates the given configuration, then creates a hub exactly as New would with the
+// equivalent options.  If the configuration is invalid, ErrInvalidConfig is returned.
+func NewFromConfig(c Config) (Dispatcher, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return New(c.Options()...), nil
+}
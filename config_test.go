@@ -0,0 +1,113 @@
+package hub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNewFromConfigUnmarshal(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c Config
+	)
+
+	require.NoError(json.Unmarshal(
+		[]byte(`{"panicPolicy": 1, "dedup": true, "deliveryOrder": 1, "workers": 3, "slowThreshold": 1000000}`),
+		&c,
+	))
+
+	assert.Equal(Recover, c.PanicPolicy)
+	assert.True(c.Dedup)
+	assert.Equal(LIFO, c.DeliveryOrder)
+	assert.Equal(3, c.Workers)
+	assert.Equal(time.Millisecond, c.SlowThreshold)
+
+	var unhandled []interface{}
+	c.Unhandled = func(e interface{}) { unhandled = append(unhandled, e) }
+
+	h, err := NewFromConfig(c)
+	require.NoError(err)
+	require.NotNil(h)
+
+	var received []string
+	l := func(s string) { received = append(received, s) }
+	first := Must(h.Subscribe(func(string) { panic("expected") }))
+	Must(h.Subscribe(l))
+	Must(h.Subscribe(l))
+
+	// LIFO with dedup delivers to l once, then recovers the panic
+	h.Publish("event")
+	assert.Equal([]string{"event"}, received)
+
+	first()
+	h.Publish(123)
+	assert.Equal([]interface{}{123}, unhandled)
+}
+
+func testNewFromConfigInvalid(t *testing.T) {
+	for name, c := range map[string]Config{
+		"PanicPolicy":         {PanicPolicy: PanicPolicy(-1)},
+		"ClosedChannelPolicy": {ClosedChannelPolicy: ClosedChannelPolicy(3)},
+		"DeliveryOrder":       {DeliveryOrder: Order(2)},
+		"ShutdownOrder":       {ShutdownOrder: Order(-1)},
+		"Strategy":            {Strategy: Strategy(2)},
+		"SlowThreshold":       {SlowThreshold: -time.Second},
+		"Workers":             {Workers: -1},
+		"QueueCapacity":       {QueueCapacity: -1},
+		"InitialBuckets":      {InitialBuckets: -1},
+		"PausePolicy":         {PausePolicy: PausePolicy(2)},
+		"PauseCapacity":       {PauseCapacity: -1},
+		"UnregisteredPolicy":  {UnregisteredPolicy: UnregisteredPolicy(4)},
+		"DrainTimeout":        {DrainTimeout: -time.Second},
+		"SinkKindOrder":       {SinkKindOrder: []SinkKind{ChanSink, SinkKind(-1)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := NewFromConfig(c)
+			assert.Equal(t, ErrInvalidConfig, err)
+			assert.Nil(t, h)
+		})
+	}
+}
+
+func testNewFromConfigFields(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c Config
+	)
+
+	require.NoError(json.Unmarshal([]byte(`{
+		"initialBuckets": 16,
+		"pausePolicy": 1,
+		"pauseCapacity": 8,
+		"rejectInvalid": true,
+		"unregisteredPolicy": 3,
+		"drainTimeout": 2000000,
+		"sinkKindOrder": [1, 0]
+	}`), &c))
+
+	d, err := NewFromConfig(c)
+	require.NoError(err)
+
+	h := d.(*hub)
+	assert.Equal(16, h.subscriptions.(*cowRegistry).capacity)
+	assert.Equal(PauseDrop, h.pause.policy)
+	assert.Equal(8, h.pause.capacity)
+	assert.True(h.rejectInvalid)
+	assert.Equal(PanicUnregistered, h.unregisteredPolicy)
+	assert.Equal(2*time.Millisecond, h.drainTimeout)
+	assert.Equal(map[SinkKind]int{ChanSink: 0, FuncSink: 1}, h.kindRanks)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	t.Run("Unmarshal", testNewFromConfigUnmarshal)
+	t.Run("Invalid", testNewFromConfigInvalid)
+	t.Run("Fields", testNewFromConfigFields)
+}
@@ -0,0 +1,66 @@
+package hub
+
+import "sync/atomic"
+
+// WithConsumerGroup places a subscription in a named consumer group.  The members of a group that subscribe to the
+// same event type share its events round-robin, so each event goes to exactly one member, while subscriptions outside
+// the group still receive every event.  This turns a hub into a simple in-process work queue:
+//
+//	for i := 0; i < 4; i++ {
+//		h.SubscribeWith(worker, hub.WithConsumerGroup("resize"))
+//	}
+//
+// Members take turns in the order they subscribed.  Disabled members are passed over, and members may be added or
+// cancelled at any time.  A cancelled member simply stops taking turns, and the rotation continues among the others.
+// Groups are distinct for each event type, and, like other subscriptions, members for an interface type or keyed
+// route form a group separate from the members for a concrete type.
+func WithConsumerGroup(name string) SubscribeOption {
+	return func(s *Subscription) {
+		s.group = name
+	}
+}
+
+// groupKey identifies a consumer group within a hub
+type groupKey struct {
+	route interface{}
+	name  string
+}
+
+// chooseMembers selects, for each consumer group in a bucket, the member that receives the next event.
+// the except subscription, which may be nil, is passed over just as disabled members are.
+func (h *hub) chooseMembers(sinks []*Subscription, except *Subscription) map[string]*Subscription {
+	members := make(map[string][]*Subscription)
+	for _, s := range sinks {
+		if len(s.group) > 0 && s != except && s.Enabled() {
+			members[s.group] = append(members[s.group], s)
+		}
+	}
+
+	chosen := make(map[string]*Subscription, len(members))
+	for name, group := range members {
+		key := groupKey{route: group[0].route, name: name}
+		counter, ok := h.groupTurns.Load(key)
+		if !ok {
+			counter, _ = h.groupTurns.LoadOrStore(key, new(uint64))
+		}
+
+		turn := atomic.AddUint64(counter.(*uint64), 1) - 1
+		chosen[name] = group[turn%uint64(len(group))]
+	}
+
+	return chosen
+}
+
+// turn tests if a subscription should receive an event, given the members chosen for the bucket that contains
+// it.  chosen is computed lazily, only once a member of some group is encountered.
+func (h *hub) turn(s *Subscription, sinks []*Subscription, except *Subscription, chosen *map[string]*Subscription) bool {
+	if len(s.group) == 0 {
+		return true
+	}
+
+	if *chosen == nil {
+		*chosen = h.chooseMembers(sinks, except)
+	}
+
+	return (*chosen)[s.group] == s
+}
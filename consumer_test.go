@@ -0,0 +1,71 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConsumerGroupRoundRobin(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+
+		members  = make([][]int, 3)
+		subs     = make([]*Subscription, 3)
+		everyone []int
+		other    []int
+	)
+
+	for i := range members {
+		i := i
+		sub, err := h.SubscribeWith(func(e int) { members[i] = append(members[i], e) }, WithConsumerGroup("workers"))
+		require.NoError(err)
+		subs[i] = sub
+	}
+
+	Must(h.Subscribe(func(e int) { everyone = append(everyone, e) }))
+	_, err := h.SubscribeWith(func(e int) { other = append(other, e) }, WithConsumerGroup("other"))
+	require.NoError(err)
+
+	for e := 0; e < 6; e++ {
+		h.Publish(e)
+	}
+
+	assert.Equal([][]int{{0, 3}, {1, 4}, {2, 5}}, members)
+	assert.Equal([]int{0, 1, 2, 3, 4, 5}, everyone)
+	assert.Equal([]int{0, 1, 2, 3, 4, 5}, other)
+
+	// the rotation continues among the remaining members
+	subs[1].Cancel()
+	subs[2].SetEnabled(false)
+	h.Publish(6)
+	h.Publish(7)
+	assert.Equal([][]int{{0, 3, 6, 7}, {1, 4}, {2, 5}}, members)
+}
+
+func testConsumerGroupReport(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := h.SubscribeWith(func(string) {}, WithConsumerGroup("workers"))
+		require.NoError(err)
+	}
+
+	report := h.PublishReport("event")
+	assert.Equal(1, report.Count(Delivered))
+	assert.Equal(1, report.Count(Skipped))
+}
+
+func TestConsumerGroup(t *testing.T) {
+	t.Run("RoundRobin", testConsumerGroupRoundRobin)
+	t.Run("Report", testConsumerGroupReport)
+}
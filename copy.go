@@ -0,0 +1,112 @@
+package hub
+
+import "reflect"
+
+// DefaultCopyDepth is the copy depth used by WithDefensiveCopy when WithCopyDepth is not supplied
+const DefaultCopyDepth = 8
+
+// Cloner may be implemented by events that know how to copy themselves.  When WithDefensiveCopy is used,
+// a hub calls Clone once for each sink rather than copying the event via reflection.  Clone must return a
+// value with the same dynamic type as the event.  Any other result, including nil, is ignored and the event
+// is copied via reflection instead.
+//
+// Only the event itself is consulted.  Values nested within an event that does not implement Cloner are
+// copied via reflection, even if they implement Cloner.
+type Cloner interface {
+	Clone() interface{}
+}
+
+var clonerType = reflect.TypeOf((*Cloner)(nil)).Elem()
+
+// copyEvent returns a copy of an event for a single sink.  If the event implements Cloner, its Clone method
+// is used.  Otherwise, or if Clone returns an unusable result, copyValue is used with the given depth.
+func copyEvent(v reflect.Value, depth int) reflect.Value {
+	if !v.IsValid() || !v.Type().Implements(clonerType) || !v.CanInterface() {
+		return copyValue(v, depth)
+	}
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		// a nil pointer has nothing to clone, and its Clone method may not expect a nil receiver
+		return v
+	}
+
+	if c := reflect.ValueOf(v.Interface().(Cloner).Clone()); c.IsValid() && c.Type() == v.Type() {
+		return c
+	}
+
+	return copyValue(v, depth)
+}
+
+// copyValue returns a copy of v.  The depth limits how many levels of indirection, i.e. pointers,
+// slices, and maps, are cloned.  At depth 0, a plain shallow copy is made, which for a pointer means
+// the copy refers to the same pointee.  Arrays, structs, and interfaces do not consume depth, since
+// their contents are copied by value anyway.
+//
+// Unexported struct fields cannot be set via reflection, so they are always copied shallowly.
+func copyValue(v reflect.Value, depth int) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || depth < 1 {
+			return v
+		}
+
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem(), depth-1))
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() || depth < 1 {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i), depth-1))
+		}
+
+		return c
+
+	case reflect.Map:
+		if v.IsNil() || depth < 1 {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for i := v.MapRange(); i.Next(); {
+			c.SetMapIndex(i.Key(), copyValue(i.Value(), depth-1))
+		}
+
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i), depth))
+		}
+
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(copyValue(v.Field(i), depth))
+			}
+		}
+
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem(), depth))
+		return c
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,145 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CopyEvent struct {
+	Names    []string
+	Labels   map[string]string
+	Inner    *CopyEvent
+	Any      interface{}
+	Array    [2][]int
+	internal []int
+}
+
+func TestCopyValue(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		original = &CopyEvent{
+			Names:    []string{"a"},
+			Labels:   map[string]string{"k": "v"},
+			Inner:    &CopyEvent{Names: []string{"inner"}},
+			Any:      []int{1},
+			Array:    [2][]int{{1}, {2}},
+			internal: []int{1},
+		}
+	)
+
+	deep := copyValue(reflect.ValueOf(original), DefaultCopyDepth).Interface().(*CopyEvent)
+	assert.Equal(original, deep)
+	assert.False(original == deep)
+	deep.Names[0] = "changed"
+	deep.Labels["k"] = "changed"
+	deep.Inner.Names[0] = "changed"
+	deep.Any.([]int)[0] = 100
+	deep.Array[0][0] = 100
+	assert.Equal("a", original.Names[0])
+	assert.Equal("v", original.Labels["k"])
+	assert.Equal("inner", original.Inner.Names[0])
+	assert.Equal(1, original.Any.([]int)[0])
+	assert.Equal(1, original.Array[0][0])
+
+	// unexported fields are always shallow
+	deep.internal[0] = 100
+	assert.Equal(100, original.internal[0])
+	original.internal[0] = 1
+
+	// depth 1 clones the pointee, but not the slices within it
+	shallow := copyValue(reflect.ValueOf(original), 1).Interface().(*CopyEvent)
+	assert.False(original == shallow)
+	shallow.Names[0] = "changed"
+	assert.Equal("changed", original.Names[0])
+
+	// depth 0 doesn't clone anything
+	assert.True(original == copyValue(reflect.ValueOf(original), 0).Interface().(*CopyEvent))
+
+	// nils are preserved
+	assert.Equal(CopyEvent{}, copyValue(reflect.ValueOf(CopyEvent{}), DefaultCopyDepth).Interface())
+}
+
+func TestDefensiveCopy(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h     = New(WithDefensiveCopy())
+		event = CopyEvent{Names: []string{"original"}}
+		seen  []string
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := h.Subscribe(func(e CopyEvent) {
+			seen = append(seen, e.Names[0])
+			e.Names[0] = "mutated"
+		})
+
+		require.NoError(err)
+	}
+
+	h.Publish(event)
+	assert.Equal([]string{"original", "original"}, seen)
+	assert.Equal("original", event.Names[0])
+
+	// shallow copies share slices
+	h = New(WithDefensiveCopy(), WithCopyDepth(0))
+	Must(h.Subscribe(func(e CopyEvent) { e.Names[0] = "mutated" }))
+	h.Publish(event)
+	assert.Equal("mutated", event.Names[0])
+}
+
+type ClonerEvent struct {
+	Names  []string
+	clones *int
+}
+
+func (ce ClonerEvent) Clone() interface{} {
+	*ce.clones++
+	return ClonerEvent{Names: append([]string{}, ce.Names...), clones: ce.clones}
+}
+
+type BadClonerEvent struct {
+	Names []string
+}
+
+func (BadClonerEvent) Clone() interface{} {
+	return nil
+}
+
+func TestCloner(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		clones int
+		h      = New(WithDefensiveCopy())
+		event  = ClonerEvent{Names: []string{"original"}, clones: &clones}
+		seen   []string
+	)
+
+	for i := 0; i < 2; i++ {
+		Must(h.Subscribe(func(e ClonerEvent) {
+			seen = append(seen, e.Names[0])
+			e.Names[0] = "mutated"
+		}))
+	}
+
+	h.Publish(event)
+	assert.Equal(2, clones)
+	assert.Equal([]string{"original", "original"}, seen)
+	assert.Equal("original", event.Names[0])
+
+	// an unusable clone falls back to reflection
+	bad := BadClonerEvent{Names: []string{"original"}}
+	Must(h.Subscribe(func(e BadClonerEvent) { e.Names[0] = "mutated" }))
+	h.Publish(bad)
+	assert.Equal("original", bad.Names[0])
+
+	// a nil pointer is never cloned
+	var nilEvent *ClonerEvent
+	assert.True(copyEvent(reflect.ValueOf(nilEvent), DefaultCopyDepth).IsNil())
+}
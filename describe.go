@@ -0,0 +1,196 @@
+package hub
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// SinkKind identifies the kind of listener behind a subscription
+type SinkKind int
+
+const (
+	// FuncSink is a function listener
+	FuncSink SinkKind = iota
+
+	// ChanSink is a channel listener
+	ChanSink
+
+	// MethodSink is a method listener, created either from a type with exactly one method or by SubscribeMethod
+	MethodSink
+
+	// CatchAllSink is a func(interface{}) listener created by SubscribeAll or SubscribeTypes
+	CatchAllSink
+
+	// CustomSink is a Sink passed to SubscribeSink
+	CustomSink
+
+	// WriterSink is an io.Writer passed to SubscribeWriter
+	WriterSink
+
+	// TransformSink is a function passed to Transform
+	TransformSink
+
+	// BatchSink is a function passed to SubscribeBatch
+	BatchSink
+
+	// LazySink is a listener built by a factory passed to SubscribeLazy
+	LazySink
+)
+
+// String returns a human-readable name for this kind
+func (sk SinkKind) String() string {
+	switch sk {
+	case FuncSink:
+		return "func"
+	case ChanSink:
+		return "chan"
+	case MethodSink:
+		return "method"
+	case CatchAllSink:
+		return "all"
+	case CustomSink:
+		return "custom"
+	case WriterSink:
+		return "writer"
+	case TransformSink:
+		return "transform"
+	case BatchSink:
+		return "batch"
+	case LazySink:
+		return "lazy"
+	default:
+		return "SinkKind(invalid)"
+	}
+}
+
+// SubscriptionInfo is a human-readable description of a single subscription, as returned by Describe
+type SubscriptionInfo struct {
+	// EventType is the name of the subscription's event type.  For catch-all subscriptions, this is the
+	// name of the empty interface.
+	EventType string
+
+	// EventTypeName is the fully qualified name of the subscription's event type, as returned by
+	// Subscription.EventTypeName
+	EventTypeName string
+
+	// Kind is the kind of listener
+	Kind SinkKind
+
+	// Label is the label supplied via WithLabel, if any
+	Label string
+
+	// Listener describes the listener itself.  For functions and methods, this is the name reported
+	// by the runtime, e.g. "main.(*Server).OnEvent".  For other kinds of listeners, this is the Go type.
+	Listener string
+}
+
+// typeName returns the fully qualified name of a type.  see Subscription.EventTypeName.
+func typeName(t reflect.Type) string {
+	switch {
+	case len(t.Name()) > 0 && len(t.PkgPath()) > 0:
+		return t.PkgPath() + "." + t.Name()
+
+	case t.Kind() == reflect.Ptr:
+		return "*" + typeName(t.Elem())
+
+	default:
+		return t.String()
+	}
+}
+
+// funcName returns the runtime's name for a function value, or the empty string if it has none
+func funcName(f reflect.Value) string {
+	if !f.IsValid() || f.Kind() != reflect.Func || f.IsNil() {
+		return ""
+	}
+
+	if rf := runtime.FuncForPC(f.Pointer()); rf != nil {
+		return rf.Name()
+	}
+
+	return ""
+}
+
+// kindOf returns the kind of a sink.  unlike describe, it does not build a description, which keeps it cheap
+// enough to call for each delivery.
+func kindOf(s sink) SinkKind {
+	switch st := s.(type) {
+	case *sinkFunc:
+		return FuncSink
+	case *sinkChan:
+		return ChanSink
+	case *sinkMethod:
+		return MethodSink
+	case *sinkAll:
+		return CatchAllSink
+	case *sinkCustom:
+		return CustomSink
+	case *sinkWriter:
+		return WriterSink
+	case *sinkTransform:
+		return TransformSink
+	case *sinkKeyed:
+		return kindOf(st.sink)
+	case *sinkMailbox:
+		return kindOf(st.target)
+	case *sinkBatch:
+		return BatchSink
+	case *sinkLazy:
+		return LazySink
+	default:
+		return SinkKind(-1)
+	}
+}
+
+// describe returns the kind of a sink along with a description of its listener
+func describe(s sink) (SinkKind, string) {
+	switch st := s.(type) {
+	case *sinkFunc:
+		return FuncSink, funcName(st.f)
+	case *sinkChan:
+		return ChanSink, st.c.Type().String()
+	case *sinkMethod:
+		return MethodSink, funcName(st.m)
+	case *sinkAll:
+		return CatchAllSink, funcName(reflect.ValueOf(st.f))
+	case *sinkCustom:
+		return CustomSink, reflect.TypeOf(st.s).String()
+	case *sinkWriter:
+		return WriterSink, reflect.TypeOf(st.w).String()
+	case *sinkTransform:
+		return TransformSink, funcName(reflect.ValueOf(st.fn))
+	case *sinkKeyed:
+		return describe(st.sink)
+	case *sinkMailbox:
+		return describe(st.target)
+	case *sinkBatch:
+		return BatchSink, funcName(st.fn)
+	case *sinkLazy:
+		return LazySink, funcName(reflect.ValueOf(st.factory))
+	default:
+		return SinkKind(-1), reflect.TypeOf(s).String()
+	}
+}
+
+func (h *hub) Describe() []SubscriptionInfo {
+	var subs []*Subscription
+	h.subscriptions.each(func(_ interface{}, bucket []*Subscription) {
+		subs = append(subs, bucket...)
+	})
+
+	FIFO.sort(subs)
+
+	info := make([]SubscriptionInfo, 0, len(subs))
+	for _, sub := range subs {
+		kind, listener := describe(sub.sink)
+		info = append(info, SubscriptionInfo{
+			EventType:     sub.eventType.String(),
+			EventTypeName: sub.EventTypeName(),
+			Kind:          kind,
+			Label:         sub.label,
+			Listener:      listener,
+		})
+	}
+
+	return info
+}
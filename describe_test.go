@@ -0,0 +1,54 @@
+package hub
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func describedListener(TestEvent) {}
+
+func TestDescribe(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		ml = new(MultiListener)
+		h  = New()
+	)
+
+	assert.Empty(h.Describe())
+
+	_, err := h.SubscribeWith(describedListener, WithLabel("described"))
+	require.NoError(err)
+
+	Must(h.Subscribe(make(chan string)))
+	Must(h.SubscribeMethod(ml, "OnString"))
+	Must(h.SubscribeAll(func(interface{}) {}))
+	Must(h.SubscribeSink(reflect.TypeOf(0), new(recordingSink)))
+	Must(h.SubscribeWriter(reflect.TypeOf(0), new(bytes.Buffer)))
+	cancel := Must(h.Transform(reflect.TypeOf(0.0), func(e interface{}) interface{} { return e }))
+
+	info := h.Describe()
+	require.Len(info, 7)
+
+	assert.Equal(
+		SubscriptionInfo{EventType: "hub.TestEvent", EventTypeName: "github.com/johnabass/hub.TestEvent", Kind: FuncSink, Label: "described", Listener: "github.com/johnabass/hub.describedListener"},
+		info[0],
+	)
+
+	assert.Equal(SubscriptionInfo{EventType: "string", EventTypeName: "string", Kind: ChanSink, Listener: "chan string"}, info[1])
+	assert.Equal(SubscriptionInfo{EventType: "string", EventTypeName: "string", Kind: MethodSink, Listener: "github.com/johnabass/hub.(*MultiListener).OnString"}, info[2])
+	assert.Equal(CatchAllSink, info[3].Kind)
+	assert.Equal("interface {}", info[3].EventType)
+	assert.Equal(SubscriptionInfo{EventType: "int", EventTypeName: "int", Kind: CustomSink, Listener: "*hub.recordingSink"}, info[4])
+	assert.Equal(SubscriptionInfo{EventType: "int", EventTypeName: "int", Kind: WriterSink, Listener: "*bytes.Buffer"}, info[5])
+	assert.Equal(TransformSink, info[6].Kind)
+	assert.Equal("transform", info[6].Kind.String())
+
+	cancel()
+	assert.Len(h.Describe(), 6)
+}
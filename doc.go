@@ -56,5 +56,45 @@
 //
 //         h.Publish(MyEvent{Status: 123})
 //
+// A bound method value such as l.On is a function listener as described in (1), and it behaves exactly like subscribing
+// l itself or calling SubscribeMethod(l, "On").  This holds even when the method value is taken from an interface that
+// l implements.  In every case, an interface input is rejected with ErrInvalidEventType.  A method expression such as
+// MyListener.On is different:  it takes the receiver as its first input, which the hub cannot supply, so it is rejected.
+//
+// Function and method listeners may declare a second input of type Meta, which receives any metadata passed to PublishMeta:
+//
+//         h.Subscribe(func(e MyEvent, meta hub.Meta) {
+//             fmt.Println(meta.CorrelationID, e)
+//         })
+//
+// Alternatively, the second input may be of type Cancel, which receives the Cancel for the listener's own subscription.
+// This allows a listener to unsubscribe itself:
+//
+//         h.Subscribe(func(e MyEvent, cancel hub.Cancel) {
+//             if e.Done {
+//                 cancel()
+//             }
+//         })
+//
+// The second input may also be a context.Context, which receives the context passed to PublishContext or PublishGroup,
+// as modified by any Middleware.  Events published in other ways carry context.Background().  Following the usual Go
+// convention, the context may instead be the first input, followed by the event:
+//
+//         h.Subscribe(func(ctx context.Context, e MyEvent) {
+//             log.Println(ctx.Value(requestID), e)
+//         })
+//
+// Finally, the second input may be of type Remaining, which receives the number of events that follow this one in
+// the current PublishBatch.  This is 0 for the last event of a batch and for events that were not published in a batch.
+//
+// A listener may also declare a reflect.Type as its first input, followed by the event.  The reflect.Type receives the
+// type of each event, which allows a single generic function to be reused across several registrations:
+//
+//         logType := func(t reflect.Type, e MyEvent) {
+//             log.Println(t, e)
+//         }
+//
+//         h.Subscribe(logType)
+//
 // Any other type passed to Subscribe results in ErrInvalidListener.
 package hub
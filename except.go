@@ -0,0 +1,9 @@
+package hub
+
+import "reflect"
+
+func (h *hub) PublishExcept(e interface{}, except *Subscription) {
+	eventType := reflect.TypeOf(e)
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: reflect.ValueOf(e), except: except}, nil)
+}
@@ -0,0 +1,71 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPublishExceptRebroadcast(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		self     *Subscription
+		received []TestEvent
+		others   []TestEvent
+	)
+
+	listener := func(e TestEvent) {
+		received = append(received, e)
+		if e.Value < 10 {
+			h.PublishExcept(TestEvent{Value: e.Value * 10}, self)
+		}
+	}
+
+	self, err := h.SubscribeWith(listener)
+	require.NoError(err)
+
+	// a second subscription for the same listener is a different subscription
+	_, err = h.SubscribeWith(func(e TestEvent) { others = append(others, e) })
+	require.NoError(err)
+
+	h.Publish(TestEvent{Value: 1})
+	assert.Equal([]TestEvent{{Value: 1}}, received)
+	assert.Equal([]TestEvent{{Value: 10}, {Value: 1}}, others)
+
+	// a nil subscription excludes nothing
+	h.PublishExcept(TestEvent{Value: 20}, nil)
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 20}}, received)
+}
+
+func testPublishExceptConsumerGroup(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		received [2][]int
+	)
+
+	first, err := h.SubscribeWith(func(e int) { received[0] = append(received[0], e) }, WithConsumerGroup("workers"))
+	require.NoError(err)
+
+	_, err = h.SubscribeWith(func(e int) { received[1] = append(received[1], e) }, WithConsumerGroup("workers"))
+	require.NoError(err)
+
+	// the excluded member is passed over, so the rest of the group still receives every event
+	for i := 0; i < 3; i++ {
+		h.PublishExcept(i, first)
+	}
+
+	assert.Empty(received[0])
+	assert.Equal([]int{0, 1, 2}, received[1])
+}
+
+func TestPublishExcept(t *testing.T) {
+	t.Run("Rebroadcast", testPublishExceptRebroadcast)
+	t.Run("ConsumerGroup", testPublishExceptConsumerGroup)
+}
@@ -0,0 +1,130 @@
+//go:build go1.21
+// +build go1.21
+
+package hub
+
+import "sync"
+
+// Subscribe is a strongly typed wrapper around Subscriber.Subscribe.  The event type is inferred
+// from fn, so signature mistakes are caught at compile time.  Interface event types are still rejected
+// at runtime with ErrInvalidEventType, unless registered via RegisterImplementation.
+func Subscribe[E any](s Subscriber, fn func(E), afterCancel ...func()) (Cancel, error) {
+	return s.Subscribe(fn, afterCancel...)
+}
+
+// Publish is a strongly typed wrapper around Publisher.Publish.  It is primarily useful to document,
+// at the call site, the event type being published.
+func Publish[E any](p Publisher, e E) {
+	p.Publish(e)
+}
+
+// As narrows an event to the type E, returning false if the event is not an E.  When E is an interface,
+// As succeeds for any event that implements it.  This is intended for catch-all listeners registered
+// with SubscribeAll, which can try each type of interest in turn without a type switch:
+//
+//	h.SubscribeAll(func(e interface{}) {
+//		if created, ok := hub.As[UserCreated](e); ok {
+//			// handle created
+//		} else if err, ok := hub.As[error](e); ok {
+//			// handle any error published as an event
+//		}
+//	})
+func As[E any](e interface{}) (E, bool) {
+	narrowed, ok := e.(E)
+	return narrowed, ok
+}
+
+// SubscribeChan subscribes a new channel of E with the given buffer, returning its receive end along with a Cancel
+// that both unsubscribes and closes the channel.  This packages the common pattern of making a channel, subscribing
+// it, and ranging over it until the subscription ends:
+//
+//	events, cancel, err := hub.SubscribeChan[UserCreated](h, 10)
+//	if err != nil {
+//		return err
+//	}
+//
+//	defer cancel()
+//	for e := range events {
+//		// handle e
+//	}
+//
+// The channel is closed however the subscription ends, including when the hub is closed.  Closing is safe with
+// respect to concurrent publishes:  a publish that is blocked sending to the channel gives up, the channel is only
+// closed once no publish is sending to it, and events published after that are discarded.
+func SubscribeChan[E any](s Subscriber, buffer int) (<-chan E, Cancel, error) {
+	var (
+		events = make(chan E, buffer)
+		done   = make(chan struct{})
+
+		// sending is held for reading by each send, so that closing can wait for sends in progress
+		sending sync.RWMutex
+	)
+
+	listener := func(e E) {
+		sending.RLock()
+		defer sending.RUnlock()
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		select {
+		case events <- e:
+		case <-done:
+		}
+	}
+
+	cancel, err := s.Subscribe(listener, func() {
+		close(done)
+		sending.Lock()
+		close(events)
+		sending.Unlock()
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, cancel, nil
+}
+
+// FuncFromChan adapts a channel into a function listener that sends each event to ch.  Like a channel
+// subscribed directly, the returned function blocks until ch accepts the event.  This lets code that works
+// with function listeners, such as RunWith or a Middleware chain, feed an existing channel consumer.
+func FuncFromChan[E any](ch chan<- E) func(E) {
+	return func(e E) {
+		ch <- e
+	}
+}
+
+// ChanFromFunc adapts a function listener into a channel with the given buffer.  A goroutine receives
+// from the channel and passes each event to fn, in order, so the channel may be subscribed anywhere a
+// channel listener is expected.
+//
+// The returned stop function closes the channel and waits until fn has handled every event that was
+// already sent to it.  stop is idempotent.  Since it closes the channel, stop must only be called once
+// nothing else will send to it, e.g. after cancelling the channel's subscription.
+func ChanFromFunc[E any](fn func(E), buffer int) (chan E, func()) {
+	var (
+		events = make(chan E, buffer)
+		done   = make(chan struct{})
+		once   sync.Once
+	)
+
+	go func() {
+		defer close(done)
+		for e := range events {
+			fn(e)
+		}
+	}()
+
+	return events, func() {
+		once.Do(func() {
+			close(events)
+		})
+
+		<-done
+	}
+}
@@ -0,0 +1,177 @@
+//go:build go1.21
+// +build go1.21
+
+package hub
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericPublishSubscribe(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received    []TestEvent
+		afterCancel bool
+		h           = New()
+	)
+
+	cancel, err := Subscribe(h, func(e TestEvent) { received = append(received, e) }, func() { afterCancel = true })
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	Publish(h, TestEvent{Value: 1})
+	Publish(h, "ignored")
+	cancel()
+	Publish(h, TestEvent{Value: 2})
+
+	assert.Equal([]TestEvent{{Value: 1}}, received)
+	assert.True(afterCancel)
+
+	cancel, err = Subscribe(h, func(io.Reader) {})
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+}
+
+func TestAs(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		events  []TestEvent
+		readers int
+		h       = New()
+	)
+
+	Must(h.SubscribeAll(func(e interface{}) {
+		if te, ok := As[TestEvent](e); ok {
+			events = append(events, te)
+		} else if _, ok := As[io.Reader](e); ok {
+			readers++
+		}
+	}))
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(strings.NewReader("reader"))
+	h.Publish("ignored")
+	h.Publish(nil)
+
+	assert.Equal([]TestEvent{{Value: 1}}, events)
+	assert.Equal(1, readers)
+
+	zero, ok := As[TestEvent](nil)
+	assert.False(ok)
+	assert.Zero(zero)
+}
+
+func TestSubscribeChan(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	events, cancel, err := SubscribeChan[TestEvent](h, 1)
+	require.NoError(err)
+	require.NotNil(events)
+	require.NotNil(cancel)
+
+	h.Publish(TestEvent{Value: 1})
+	assert.Equal(TestEvent{Value: 1}, <-events)
+
+	// a publish blocked on the full channel is released by cancellation
+	h.Publish(TestEvent{Value: 2})
+	published := make(chan struct{})
+	go func() {
+		h.Publish(TestEvent{Value: 3})
+		close(published)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-published
+
+	var received []TestEvent
+	for e := range events {
+		received = append(received, e)
+	}
+
+	assert.Equal([]TestEvent{{Value: 2}}, received)
+	assert.NotPanics(func() { cancel() })
+
+	// closing the hub closes the channel
+	strs, _, err := SubscribeChan[string](h, 0)
+	require.NoError(err)
+	h.Close()
+	_, ok := <-strs
+	assert.False(ok)
+
+	events, cancel, err = SubscribeChan[TestEvent](h, 0)
+	assert.Equal(ErrClosed, err)
+	assert.Nil(events)
+	assert.Nil(cancel)
+
+	readers, cancel, err := SubscribeChan[io.Reader](New(), 0)
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(readers)
+	assert.Nil(cancel)
+}
+
+func TestFuncFromChan(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h      = New()
+		events = make(chan TestEvent, 2)
+	)
+
+	cancel, err := Subscribe(h, FuncFromChan(events))
+	require.NoError(err)
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(TestEvent{Value: 2})
+	assert.Equal(TestEvent{Value: 1}, <-events)
+	assert.Equal(TestEvent{Value: 2}, <-events)
+
+	cancel()
+	h.Publish(TestEvent{Value: 3})
+	assert.Empty(events)
+}
+
+func TestChanFromFunc(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		received []TestEvent
+	)
+
+	events, stop := ChanFromFunc(func(e TestEvent) { received = append(received, e) }, 5)
+	require.NotNil(events)
+	require.NotNil(stop)
+
+	cancel, err := h.Subscribe(events)
+	require.NoError(err)
+
+	for i := 0; i < 5; i++ {
+		h.Publish(TestEvent{Value: i})
+	}
+
+	// stop waits for every event already sent to be handled
+	cancel()
+	stop()
+	assert.Equal([]TestEvent{{Value: 0}, {Value: 1}, {Value: 2}, {Value: 3}, {Value: 4}}, received)
+
+	_, ok := <-events
+	assert.False(ok)
+	assert.NotPanics(stop)
+}
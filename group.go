@@ -0,0 +1,97 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// PanicError is the error reported by PublishGroup when a listener panics with a value that is not itself an error.
+// It is also reported when a factory passed to SubscribeLazy panics.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value interface{}
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("listener panicked: %v", pe.Value)
+}
+
+// panicError converts a recovered value into an error
+func panicError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+
+	return &PanicError{Value: r}
+}
+
+// groupState collects the outcome of the concurrent deliveries made by a single PublishGroup
+type groupState struct {
+	// parent is the context passed to PublishGroup, and cancel cancels the context derived from it
+	parent context.Context
+	cancel context.CancelFunc
+
+	wg    sync.WaitGroup
+	once  sync.Once
+	first error
+}
+
+// fail records a failed delivery.  the first failure is kept, and cancels the remaining deliveries.
+func (g *groupState) fail(err error) {
+	g.once.Do(func() {
+		g.first = err
+		g.cancel()
+	})
+}
+
+// detach returns this message as Publish would have produced it.  a message published with PublishGroup
+// but held by Pause is delivered after PublishGroup has returned, so it can no longer be part of the group.
+func (m message) detach() message {
+	if m.group != nil {
+		m.ctx, m.group = m.group.parent, nil
+	}
+
+	return m
+}
+
+// deliverConcurrently delivers a message published with PublishGroup to a single subscription on its own
+// goroutine.  panics are always recovered and fail the group.  last indicates that the subscription has
+// reached its limit, in which case it is cancelled once this delivery finishes.
+func (h *hub) deliverConcurrently(s *Subscription, m message, last bool) {
+	g := m.group
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if last {
+			defer s.Cancel()
+		}
+
+		proceed, r, panicked := h.trySend(s, m)
+		switch {
+		case panicked:
+			err := panicError(r)
+			h.delivered(s, err)
+			g.fail(err)
+
+		case proceed:
+			h.delivered(s, nil)
+		}
+	}()
+}
+
+func (h *hub) PublishGroup(ctx context.Context, e interface{}) error {
+	var (
+		eventType = reflect.TypeOf(e)
+		g         = &groupState{parent: ctx}
+	)
+
+	ctx, g.cancel = context.WithCancel(ctx)
+	defer g.cancel()
+
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: reflect.ValueOf(e), ctx: ctx, group: g}, nil)
+	g.wg.Wait()
+	return g.first
+}
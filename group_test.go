@@ -0,0 +1,167 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPublishGroupSuccess(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		count int32
+		h     = New()
+	)
+
+	for i := 0; i < 5; i++ {
+		Must(h.Subscribe(func(string) { atomic.AddInt32(&count, 1) }))
+	}
+
+	assert.NoError(h.PublishGroup(context.Background(), "command"))
+	assert.Equal(int32(5), atomic.LoadInt32(&count))
+
+	// unhandled events are not errors
+	assert.NoError(h.PublishGroup(context.Background(), 123))
+}
+
+func testPublishGroupError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		expected = errors.New("expected")
+		started  = make(chan struct{})
+		canceled int32
+		h        = New()
+	)
+
+	Must(h.Subscribe(func(_ string, ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		atomic.StoreInt32(&canceled, 1)
+	}))
+
+	Must(h.Subscribe(func(string) {
+		<-started
+		panic(expected)
+	}))
+
+	assert.Equal(expected, h.PublishGroup(context.Background(), "command"))
+	assert.Equal(int32(1), atomic.LoadInt32(&canceled))
+}
+
+func testPublishGroupPanicValue(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	Must(h.Subscribe(func(string) { panic("not an error") }))
+
+	err := h.PublishGroup(context.Background(), "command")
+	assert.Equal(&PanicError{Value: "not an error"}, err)
+	assert.Equal("listener panicked: not an error", err.Error())
+}
+
+func testPublishGroupReturnedError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		expected = errors.New("expected")
+		handled  []error
+		h        = New(WithErrorHandler(func(err error) { handled = append(handled, err) }))
+	)
+
+	Must(h.SubscribeErr(func(string) error { return nil }))
+	Must(h.SubscribeErr(func(e string) error {
+		if e == "fail" {
+			return expected
+		}
+
+		return nil
+	}))
+
+	assert.NoError(h.PublishGroup(context.Background(), "succeed"))
+	assert.Equal(expected, h.PublishGroup(context.Background(), "fail"))
+
+	// PublishGroup returns errors rather than passing them to the error handler, while Publish does the opposite
+	assert.Empty(handled)
+	h.Publish("fail")
+	assert.Equal([]error{expected}, handled)
+}
+
+func testPublishGroupPipeline(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		intercepted int32
+		child       int32
+		h           = New(WithMiddleware(func(ctx context.Context, e interface{}, next func(context.Context)) {
+			atomic.AddInt32(&intercepted, 1)
+			next(ctx)
+		}))
+	)
+
+	// events reach the rest of a hierarchy, and errors from any hub in it are returned
+	c := NewChild(h)
+	Must(c.SubscribeErr(func(string) error {
+		atomic.AddInt32(&child, 1)
+		return errors.New("child failed")
+	}))
+
+	assert.EqualError(h.PublishGroup(context.Background(), "command"), "child failed")
+	assert.Equal(int32(1), atomic.LoadInt32(&intercepted))
+	assert.Equal(int32(1), atomic.LoadInt32(&child))
+}
+
+func testSubscribeErr(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output   bytes.Buffer
+		expected = errors.New("expected")
+		h        = New(WithLogger(log.New(&output, "", 0)))
+	)
+
+	for _, record := range []struct {
+		listener interface{}
+		err      error
+	}{
+		{listener: nil, err: ErrInvalidListener},
+		{listener: make(chan int), err: ErrInvalidListener},
+		{listener: func(int) {}, err: ErrInvalidFunction},
+		{listener: func(int) int { return 0 }, err: ErrInvalidFunction},
+		{listener: func(int, int) error { return nil }, err: ErrInvalidFunction},
+		{listener: func(io.Reader) error { return nil }, err: ErrInvalidEventType},
+	} {
+		cancel, err := h.SubscribeErr(record.listener)
+		assert.Equal(record.err, err)
+		assert.Nil(cancel)
+	}
+
+	Must(h.SubscribeErr(func(context.Context, int) error { return expected }))
+
+	// without an error handler, a listener's error is logged rather than dropped
+	report := h.PublishReport(1)
+	require.Len(report.Results, 1)
+	assert.Equal(Errored, report.Results[0].Outcome)
+	assert.Equal(expected, report.Results[0].Err)
+	assert.Equal("listener for int returned an error: expected\n", output.String())
+}
+
+func TestPublishGroup(t *testing.T) {
+	t.Run("Success", testPublishGroupSuccess)
+	t.Run("Error", testPublishGroupError)
+	t.Run("PanicValue", testPublishGroupPanicValue)
+	t.Run("ReturnedError", testPublishGroupReturnedError)
+	t.Run("Pipeline", testPublishGroupPipeline)
+	t.Run("SubscribeErr", testSubscribeErr)
+}
@@ -0,0 +1,28 @@
+package hub
+
+import "time"
+
+func (h *hub) HealthCheck(timeout time.Duration) error {
+	h.asyncOnce.Do(h.startWorkers)
+	if h.jobs == nil {
+		return ErrClosed
+	}
+
+	// the probe has no weight, so it is admitted even when the queue is full.  it is never dispatched, so
+	// no listener can observe it, and a probe abandoned after a timeout is simply discarded when taken.
+	probe := make(chan struct{})
+	if _, ok := h.jobs.put(job{probe: probe}); !ok {
+		return ErrClosed
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-probe:
+		return nil
+
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,42 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		gate     = make(chan struct{})
+		received []interface{}
+
+		h = New(WithGlobalQueue(1))
+	)
+
+	Must(h.SubscribeAll(func(e interface{}) { received = append(received, e) }))
+	assert.NoError(h.HealthCheck(time.Second))
+
+	// a wedged listener blocks the only worker, even though the queue is full
+	Must(h.Subscribe(func(int) { <-gate }))
+	assert.True(h.PublishAsync(1))
+	assert.Equal(ErrTimeout, h.HealthCheck(10*time.Millisecond))
+
+	close(gate)
+	assert.NoError(h.HealthCheck(time.Second))
+	h.Flush()
+
+	// probes never reach listeners
+	assert.Equal([]interface{}{1}, received)
+
+	h.Close()
+	assert.Equal(ErrClosed, h.HealthCheck(time.Second))
+
+	// a hub closed before any asynchronous publish has no workers to check
+	unused := New()
+	unused.Close()
+	assert.Equal(ErrClosed, unused.HealthCheck(time.Second))
+}
@@ -0,0 +1,110 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrInvalidParent indicates that NewChild was passed a parent that was not created by this package
+var ErrInvalidParent = errors.New("A parent hub must be created by this package")
+
+// WithParentEvents controls whether a hub created by NewChild receives events published to its parent.
+// The default is true.  This option has no effect on hubs created with New.
+func WithParentEvents(enabled bool) Option {
+	return func(h *hub) {
+		h.ignoreParent = !enabled
+	}
+}
+
+// NewChild creates a hub that is attached to a parent, allowing a module to have its own hub while
+// still participating in application-wide events.
+//
+// An event published to the child is delivered to the child's listeners, then bubbles up to the parent,
+// which delivers it to the parent's listeners.  An event published to the parent is delivered to the parent's
+// listeners, then to the listeners of each child in the order the children were created, unless a child was
+// created with WithParentEvents(false).  An event never returns to the hub it was forwarded from, so an event
+// bubbling up from a child is not delivered back down to that child, though its siblings do receive it.
+//
+// These rules apply recursively, so a hierarchy may be arbitrarily deep.  Each hub applies its own options,
+// such as its panic policy, to its own listeners.  An event is unhandled only if no hub in the hierarchy
+// delivered it, in which case only the publishing hub's WithUnhandled hook or fallback is invoked.
+//
+// Closing a child detaches it from its parent.  Closing a parent does not close its children.
+//
+// NewChild panics with ErrInvalidParent if parent was not created by this package.
+func NewChild(parent Interface, options ...Option) Dispatcher {
+	p, ok := parent.(*hub)
+	if !ok {
+		panic(ErrInvalidParent)
+	}
+
+	h := New(options...).(*hub)
+	h.parent.Store(p)
+	if !h.ignoreParent {
+		p.attach(h)
+	}
+
+	return h
+}
+
+// loadParent returns this hub's parent, or nil if this hub has none or has been closed
+func (h *hub) loadParent() *hub {
+	p, _ := h.parent.Load().(*hub)
+	return p
+}
+
+// loadChildren returns the children that receive this hub's events
+func (h *hub) loadChildren() []*hub {
+	c, _ := h.children.Load().([]*hub)
+	return c
+}
+
+// attach adds a child that receives this hub's events.  like subscription buckets, the slice of
+// children is copy-on-write.
+func (h *hub) attach(child *hub) {
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	existing := h.loadChildren()
+	updated := make([]*hub, len(existing), len(existing)+1)
+	copy(updated, existing)
+	h.children.Store(append(updated, child))
+}
+
+// detach removes a child, if present
+func (h *hub) detach(child *hub) {
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	existing := h.loadChildren()
+	updated := make([]*hub, 0, len(existing))
+	for _, c := range existing {
+		if c != child {
+			updated = append(updated, c)
+		}
+	}
+
+	h.children.Store(updated)
+}
+
+// forward sends a message on to the other hubs in this hub's hierarchy, excluding the hub the message
+// came from.  it returns true if any hub delivered the message.
+func (h *hub) forward(eventType reflect.Type, m message) bool {
+	var (
+		from    = m.origin
+		handled bool
+	)
+
+	m.origin = h
+	for _, c := range h.loadChildren() {
+		if c != from {
+			handled = c.propagate(eventType, m) || handled
+		}
+	}
+
+	if p := h.loadParent(); p != nil && p != from {
+		handled = p.propagate(eventType, m) || handled
+	}
+
+	return handled
+}
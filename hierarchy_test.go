@@ -0,0 +1,85 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testNewChildRouting(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received []string
+		listener = func(name string) func(string) {
+			return func(e string) { received = append(received, name+":"+e) }
+		}
+
+		parent     = New()
+		child      = NewChild(parent)
+		sibling    = NewChild(parent)
+		grandchild = NewChild(child)
+		isolated   = NewChild(parent, WithParentEvents(false))
+	)
+
+	Must(parent.Subscribe(listener("parent")))
+	Must(child.Subscribe(listener("child")))
+	Must(sibling.Subscribe(listener("sibling")))
+	Must(grandchild.Subscribe(listener("grandchild")))
+	Must(isolated.Subscribe(listener("isolated")))
+
+	parent.Publish("a")
+	assert.Equal([]string{"parent:a", "child:a", "grandchild:a", "sibling:a"}, received)
+
+	received = nil
+	grandchild.Publish("b")
+	assert.Equal([]string{"grandchild:b", "child:b", "parent:b", "sibling:b"}, received)
+
+	// an isolated child still bubbles up
+	received = nil
+	isolated.Publish("c")
+	assert.Equal([]string{"isolated:c", "parent:c", "child:c", "grandchild:c", "sibling:c"}, received)
+
+	// a closed child is detached
+	received = nil
+	child.Close()
+	parent.Publish("d")
+	assert.Equal([]string{"parent:d", "sibling:d"}, received)
+
+	received = nil
+	grandchild.Publish("e")
+	assert.Equal([]string{"grandchild:e"}, received)
+}
+
+func testNewChildUnhandled(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		parentUnhandled []interface{}
+		childUnhandled  []interface{}
+
+		parent = New(WithUnhandled(func(e interface{}) { parentUnhandled = append(parentUnhandled, e) }))
+		child  = NewChild(parent, WithUnhandled(func(e interface{}) { childUnhandled = append(childUnhandled, e) }))
+	)
+
+	Must(parent.Subscribe(func(int) {}))
+
+	// handled by the parent
+	assert.True(child.PublishOK(1))
+
+	assert.False(child.PublishOK("unhandled"))
+	assert.Equal([]interface{}{"unhandled"}, childUnhandled)
+	assert.Empty(parentUnhandled)
+}
+
+func testNewChildInvalidParent(t *testing.T) {
+	assert.PanicsWithValue(t, ErrInvalidParent, func() {
+		NewChild(nil)
+	})
+}
+
+func TestNewChild(t *testing.T) {
+	t.Run("Routing", testNewChildRouting)
+	t.Run("Unhandled", testNewChildUnhandled)
+	t.Run("InvalidParent", testNewChildInvalidParent)
+}
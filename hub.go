@@ -1,9 +1,33 @@
 package hub
 
 import (
+	"context"
+	"errors"
+	"io"
+	"log"
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrClosed indicates an attempt to subscribe to a hub that has been closed
+	ErrClosed = errors.New("The hub has been closed")
+
+	// ErrTimeout indicates that an operation did not complete within its allotted time
+	ErrTimeout = errors.New("The operation timed out")
+
+	// ErrFrozen indicates an attempt to subscribe to a hub that has been frozen with Freeze
+	ErrFrozen = errors.New("The hub is frozen")
+
+	// ErrInvalidValue indicates that PublishValue was passed a value that was invalid, of an interface kind,
+	// or obtained through unexported struct fields
+	ErrInvalidValue = errors.New("A published value must be valid, exported, and not an interface")
+
+	// ErrDuplicateListener indicates an attempt to subscribe a listener that is identical to one already
+	// registered for the same event type, on a hub created with WithNoDuplicates
+	ErrDuplicateListener = errors.New("The listener is already subscribed")
 )
 
 // Cancel is a cancellation closure for subscriptions.  Cancels are idempotent.
@@ -22,9 +46,16 @@ type Cancel func()
 type Publisher interface {
 	// Publish routes an arbitrary object to subscribers.
 	//
-	// Publish is synchronous, so listeners should not perform long-running tasks
-	// without spawning a goroutine.  If any listener panics, that panic will interrupt
-	// event delivery and the panic will escape the call to Publish.
+	// Publish is synchronous by default, so listeners should not perform long-running tasks
+	// without spawning a goroutine.  By default, if any listener panics, that panic will interrupt
+	// event delivery and the panic will escape the call to Publish.  See WithPanicPolicy, and see
+	// WithStrategy and WithTypeStrategy for delivering some or all events asynchronously.
+	//
+	// A nil event has no type, so it is only delivered to catch-all listeners, which receive nil.
+	// If there are no catch-all listeners, a nil event is unhandled.
+	//
+	// An event is routed by its concrete type, which is never an interface.  Listeners for an interface type
+	// only receive events whose concrete types were registered with RegisterImplementation.
 	Publish(interface{})
 }
 
@@ -39,17 +70,458 @@ type Subscriber interface {
 	Subscribe(l interface{}, afterCancel ...func()) (Cancel, error)
 }
 
-// Interface provides both publish and subscribe functionality
+// Interface provides both publish and subscribe functionality.  It is deliberately small and stable, so that
+// code which only publishes and subscribes can depend on it and substitute its own implementation, e.g. a mock.
 type Interface interface {
 	Publisher
 	Subscriber
 }
 
+// Dispatcher is the full set of capabilities of the hubs created by this package, e.g. by New.  Capabilities are
+// added to Dispatcher over time, so it is not meant to be implemented outside this package.  Code that needs to
+// substitute its own implementation should depend on Interface, Publisher, or Subscriber instead.
+type Dispatcher interface {
+	Interface
+
+	// PublishOrElse routes an event to subscribers exactly like Publish.  If no sinks matched the event,
+	// fallback is invoked with the event instead.  This is useful for command-style events where a caller
+	// needs to react when nothing handled the event.
+	//
+	// A non-nil fallback takes precedence over any hook configured via WithUnhandled.  Only one of them
+	// is invoked for an unhandled event.  If fallback is nil, this method behaves exactly like Publish.
+	PublishOrElse(e interface{}, fallback func(interface{}))
+
+	// SubscribeWriter registers an io.Writer for events of the given type.  Each event is JSON-encoded
+	// and written to w followed by a newline.  Writes are serialized, so w need not be safe for concurrent use.
+	//
+	// Encoding and write errors are passed to the hub's error handler, if one was configured via WithErrorHandler.
+	// Otherwise, such errors are ignored.
+	SubscribeWriter(eventType reflect.Type, w io.Writer) (Cancel, error)
+
+	// SubscribeWith registers a new listener in the same manner as Subscribe, but returns a
+	// Subscription handle rather than a bare Cancel.  Any afterCancel closures are supplied
+	// via WithAfterCancel.  If an error occurs, the returned Subscription will be nil.
+	SubscribeWith(l interface{}, options ...SubscribeOption) (*Subscription, error)
+
+	// Unsubscribe cancels every subscription of a listener, identified by the listener value itself rather than
+	// by a Cancel.  The listener is examined exactly as Subscribe examines it, and the subscriptions for its event
+	// type whose listeners are identical to l are cancelled, including any afterCancel closures.  Identity follows
+	// the rules described for WithDedup, so for example a separately created bound method value never matches.
+	// A func(interface{}) matches listeners passed to SubscribeAll.
+	//
+	// This method returns true if at least one subscription was cancelled.  If l is not a valid listener, the
+	// same error that Subscribe would return is returned.
+	Unsubscribe(l interface{}) (bool, error)
+
+	// SubscribeAfter registers a listener in the same manner as Subscribe, except that the listener ignores
+	// the first n-1 events it receives and is invoked starting with the nth.  This is shorthand for SubscribeWith
+	// and WithSkip(n-1).  A value of n less than 2 delivers every event.
+	SubscribeAfter(n int, l interface{}, afterCancel ...func()) (Cancel, error)
+
+	// SubscribeMethod registers the named method of receiver as a listener.  Unlike Subscribe, receiver
+	// may have any number of methods.  The named method must be exported and must have the same signature
+	// as a function listener, i.e. exactly (1) input and no outputs.
+	//
+	// If receiver has no such method, ErrNoSuchMethod is returned.
+	SubscribeMethod(receiver interface{}, methodName string, afterCancel ...func()) (Cancel, error)
+
+	// WaitForSubscriber blocks until at least one sink exists for the given event type or until the context
+	// is done.  If the context ends first, the context's error is returned.  This is useful to coordinate
+	// startup between producers and consumers, so that early events are not lost.
+	WaitForSubscriber(ctx context.Context, eventType reflect.Type) error
+
+	// SubscribeAll registers a catch-all listener that receives every published event, regardless of type.
+	// Catch-all listeners receive an event after all listeners registered for its specific type.
+	// The Subscription for a catch-all listener reports the empty interface as its event type.
+	SubscribeAll(l func(interface{}), afterCancel ...func()) (Cancel, error)
+
+	// SubscribeTypes registers a single listener for each of several event types.  Unlike SubscribeAll, the
+	// listener only receives events whose types are in the given list.  Interface types are subject to the same
+	// rules as Subscribe, and ErrInvalidEventType is returned if types is empty or contains nil.  Nothing is
+	// registered if an error occurs.
+	//
+	// The returned Cancel removes the listener for every type.  The afterCancel closures run once, after the
+	// listener has been removed for all types, whether by the returned Cancel, CancelType, or Close.
+	SubscribeTypes(l func(interface{}), types []reflect.Type, afterCancel ...func()) (Cancel, error)
+
+	// SubscribeFor registers a listener for exactly one event type, delivering each event as an interface{}.
+	// Unlike SubscribeAll, the listener only receives events routed to eventType, so it need not switch on
+	// the type of each event.  This is shorthand for SubscribeTypes with a single type.
+	SubscribeFor(eventType reflect.Type, l func(interface{}), afterCancel ...func()) (Cancel, error)
+
+	// SubscribeLazy registers a listener for eventType that is not built until it is needed.  factory is invoked
+	// the first time an event would be delivered to the subscription, and the listener it returns receives that
+	// event and every later one.  If no event ever arrives, factory is never invoked, which avoids constructing
+	// listeners that are expensive to create but rarely used.
+	//
+	// The listener returned by factory is examined exactly as Subscribe examines it, and its event type must be
+	// eventType.  If it is not a valid listener for eventType, the error is passed to the hub's error handler and
+	// the subscription discards every event.  factory is invoked at most once.  If eventType is nil or not a valid
+	// event type, ErrInvalidEventType is returned, and if factory is nil, ErrInvalidListener is returned.
+	SubscribeLazy(eventType reflect.Type, factory func() interface{}) (Cancel, error)
+
+	// RegisterEventTypeNamed associates a name with an event type, for use with SubscribeByTypeName.  This allows
+	// code that only knows event types by name, such as plugins wired from configuration, to subscribe to them.
+	// Registering the same name and type again has no effect.  If the name is empty, eventType is nil, or the
+	// name is already registered for a different type, ErrInvalidTypeName is returned.
+	RegisterEventTypeNamed(name string, eventType reflect.Type) error
+
+	// RegisterEventType declares that eventType is a known event type.  Declaring types is only necessary with
+	// WithUnregisteredPolicy, which determines how publishes of undeclared types are handled.  Types registered
+	// with RegisterEventTypeNamed are declared as well.  Registering a type more than once has no further effect.
+	// If eventType is nil, ErrInvalidEventType is returned.
+	RegisterEventType(eventType reflect.Type) error
+
+	// SubscribeByTypeName registers a listener for the event type registered under the given name.  Events
+	// of that type are delivered to l exactly as SubscribeFor delivers them, and name-based subscriptions
+	// coexist with any other subscriptions for the type.  The name is resolved when this method is called.
+	// If no type is registered under the name, ErrUnknownTypeName is returned.
+	SubscribeByTypeName(name string, l func(interface{}), afterCancel ...func()) (Cancel, error)
+
+	// Tee forwards every event published to this hub on to dst.  The returned Cancel stops forwarding.
+	//
+	// Forwarding is synchronous and unconditional, so care must be taken not to create cycles, e.g. by teeing
+	// hub A to hub B and hub B back to hub A.  Such a cycle recurses until the stack overflows.  Teeing a hub
+	// to itself is detected and does nothing.
+	Tee(dst Publisher) Cancel
+
+	// TeeWithReplay is like Tee, but first forwards each sticky event retained by this hub, in no particular
+	// order, so that dst starts out with this hub's current state.  Live forwarding begins once the sticky events
+	// have been forwarded.  Sticky events are captured in the same step that starts forwarding, so none is missed,
+	// though one published concurrently with this method may be forwarded twice.
+	TeeWithReplay(dst Publisher) Cancel
+
+	// Close cancels every subscription, invoking any afterCancel closures, and prevents new subscriptions.
+	// After Close, Subscribe and its variants return ErrClosed and published events are unhandled.
+	// This method is idempotent.
+	Close()
+
+	// CloseAndDrain closes this hub, then waits up to timeout for consumer goroutines to finish.  Consumers
+	// are tracked by the WaitGroup passed to WithConsumerWaitGroup.  Channels subscribed with WithCloseOnCancel
+	// are closed as part of closing the hub, so consumers ranging over them will exit cleanly.  The goroutines
+	// of subscriptions made with WithMailbox are waited on as well, including any drain under WithDrainOnCancel.
+	//
+	// If the consumers do not finish in time, ErrTimeout is returned.  If there are no consumers or mailboxes to
+	// wait on, this method returns as soon as the hub is closed.
+	CloseAndDrain(timeout time.Duration) error
+
+	// PublishMeta routes an event to subscribers along with the given metadata.  Listeners that declare
+	// a Meta parameter receive meta, while all other listeners receive the event as though it were passed to
+	// Publish.  If meta.Time is zero, it is set to the current time.
+	PublishMeta(e interface{}, meta Meta)
+
+	// Scope creates a child Subscriber whose subscriptions can be cancelled together via Scope.Close.
+	// Closing this hub also cancels every scope's subscriptions.
+	Scope() Scope
+
+	// ScopeContext creates a Scope that closes itself when ctx is done, cancelling every subscription made through
+	// it.  This suits per-request handlers, whose listeners should all go away when the request's context ends.
+	// A single goroutine waits on ctx, and closing the scope with Close releases it before ctx is done.
+	ScopeContext(ctx context.Context) Scope
+
+	// PublishOK routes an event to subscribers exactly like Publish, returning true if the event
+	// matched at least one sink, including catch-all sinks.  When this method returns false, the event
+	// was also passed to any hook configured via WithUnhandled.
+	PublishOK(e interface{}) bool
+
+	// SubscribeSink registers a custom Sink for events of the given type, bypassing the reflection
+	// that Subscribe uses to examine listeners.  As with Subscribe, the event type must be concrete
+	// unless it is an interface registered via RegisterImplementation.  Otherwise, no published event
+	// could ever match, and ErrInvalidEventType is returned.
+	SubscribeSink(eventType reflect.Type, s Sink, afterCancel ...func()) (Cancel, error)
+
+	// PublishTyped routes an event using the given type rather than calling reflect.TypeOf on the event.
+	// This is a micro-optimization for producers that publish the same type repeatedly.
+	//
+	// The caller is responsible for passing the event's actual type.  A mismatched type misroutes the event,
+	// which can cause listeners to panic.
+	PublishTyped(eventType reflect.Type, e interface{})
+
+	// PublishExcept routes an event exactly as Publish does, except that the given subscription does not receive it.
+	// This allows a listener to publish a modified event, or to rebroadcast the one it is handling, without receiving
+	// it again.  The subscription is matched by identity, so every other subscription receives the event, even one
+	// for the same listener.  If except is nil, this method is equivalent to Publish.
+	PublishExcept(e interface{}, except *Subscription)
+
+	// PublishLazy publishes the event returned by build, but only invokes build if at least one sink could
+	// receive an event of the given type.  This avoids the cost of constructing an expensive event that nobody
+	// is listening for.  Subscriptions that are disabled or have reached their WithLimit do not count, so if every
+	// matching subscription is filtered out that way, build is never invoked.  build is invoked at most once, and
+	// the event it returns is shared among the sinks just as with Publish.
+	//
+	// Whether an event is delivered sometimes depends on the event itself, e.g. with an interceptor, middleware, or
+	// keyed routing.  In those cases, build is invoked and the event is published normally.  As with PublishTyped,
+	// the event returned by build must be of type eventType.  An event that is never built is not counted as
+	// published and is not passed to any unhandled hook.
+	PublishLazy(eventType reflect.Type, build func() interface{})
+
+	// Transform registers a function that maps events of the given type into other events.  Whenever an event
+	// of type from is published, fn is invoked with it and the result is published as well, along with the same
+	// Meta.  For example, raw wire events can be normalized into domain events automatically.  If fn returns nil,
+	// nothing further is published.
+	//
+	// A transformed event routes normally, so it can match other transforms.  A transform is never applied twice
+	// while handling a single published event, which guards against cycles.  A transform is itself a sink for its
+	// source type, so it is subject to the hub's delivery order and panic policy.
+	Transform(from reflect.Type, fn func(interface{}) interface{}) (Cancel, error)
+
+	// PublishAsync enqueues an event for delivery by this hub's worker goroutines, returning false if
+	// the event was dropped.  The number of workers is set by WithWorkers, and they are started on the first
+	// call to this method.  Recipients are determined when a worker delivers the event, not when it is enqueued.
+	//
+	// By default, the queue is unbounded.  WithGlobalQueue bounds the number of pending deliveries, in which case
+	// this method blocks while the queue is full, or drops the event if WithDropWhenFull is also set.
+	//
+	// There is no caller to receive a panic from an asynchronous delivery, so any panic that escapes the hub's
+	// PanicPolicy is recovered and logged.  After Close, this method drops every event.
+	PublishAsync(e interface{}) bool
+
+	// PublishUrgent enqueues an event exactly as PublishAsync does, except that the event is delivered ahead of
+	// every event enqueued by PublishAsync that a worker has not yet started.  This is useful for events such as
+	// shutdown notices or alarms that should not wait behind a backlog.  Urgent events are delivered in the order
+	// they were enqueued, as are normal events, but the two are not ordered relative to each other.
+	//
+	// Urgent events count toward the capacity set by WithGlobalQueue, but are never blocked or dropped because the
+	// queue is full.  After Close, this method drops every event.
+	PublishUrgent(e interface{}) bool
+
+	// Flush blocks until every event enqueued by PublishAsync before this call has been delivered.
+	// Events enqueued after this call begins do not extend the wait.  This is primarily useful for
+	// deterministic tests.
+	//
+	// Concurrent calls to Flush are serialized, so each waits for any earlier flush to complete as well.  Flush
+	// must not be called from a listener that is handling an asynchronous delivery, as that would deadlock.
+	Flush()
+
+	// HealthCheck verifies that asynchronous delivery is making progress, which makes it suitable for a liveness
+	// probe.  A private probe is enqueued behind every pending asynchronous delivery, and this method waits up to
+	// timeout for a worker to take it.  If every worker is stuck in a listener, or the backlog cannot drain in
+	// time, ErrTimeout is returned.  If this hub has been closed, ErrClosed is returned.
+	//
+	// The probe is never dispatched, so no listener, including a catch-all listener, ever receives it.  Unlike
+	// Stats, this check exercises the workers themselves, starting them if necessary.
+	HealthCheck(timeout time.Duration) error
+
+	// BindContext causes this hub to Close when ctx is done.  A single goroutine waits on the context.
+	// Calling BindContext again replaces the prior binding, so only the most recently bound context closes
+	// the hub.  Closing the hub by other means releases the goroutine, and binding a closed hub does nothing.
+	BindContext(ctx context.Context)
+
+	// Stats returns a snapshot of this hub's subscriptions and activity.  This method is lock-free.
+	Stats() Stats
+
+	// PublishSticky publishes an event exactly like Publish, and also retains it as the sticky event for its type.
+	// Each subsequent subscription to that type immediately receives the sticky event, on the subscribing goroutine,
+	// before Subscribe returns.  Only the most recent sticky event of each type is retained.  This is useful for
+	// state-like events, such as the current configuration or the current leader.
+	//
+	// Catch-all subscriptions do not receive sticky events when they subscribe.  A nil event is never retained, and
+	// neither is an event that is dropped by the UnregisteredPolicy, rejected by WithRejectInvalid, dropped while
+	// paused, or stopped by middleware.  An event held by Pause is retained once it is delivered on Resume.
+	PublishSticky(e interface{})
+
+	// RegisterImplementation allows iface to be used as an event type, and routes published events of the
+	// concrete type to listeners of iface in addition to listeners of the concrete type itself.  Only registered
+	// concrete types are routed this way, so the cost of matching is paid once here rather than on each publish.
+	//
+	// Listeners for an interface type may only subscribe once that interface has been registered with at least
+	// one concrete type.  Registering the same pair more than once has no further effect.  If iface is not an
+	// interface, is the empty interface, or is not implemented by concrete, ErrInvalidImplementation is returned.
+	RegisterImplementation(iface, concrete reflect.Type) error
+
+	// Alias routes published events of type from to the listeners of type to, in addition to the listeners of
+	// from itself.  Each aliased event is converted to type to before delivery.  This eases migrations where an
+	// event type is renamed or moved between packages, since producers and consumers can change independently.
+	//
+	// The two types must share the same underlying type, e.g. two struct types with identical fields.  Types that
+	// are merely convertible, such as int and string or int32 and float64, are rejected since converting between
+	// them changes the value.  Such conversions are safe, but the caller is responsible for the types also meaning
+	// the same thing.  Aliases are not transitive, and only the listeners registered for exactly type
+	// to receive aliased events.  Aliasing from again replaces its previous alias.  If the types cannot be aliased,
+	// ErrInvalidAlias is returned.
+	Alias(from, to reflect.Type) error
+
+	// RegisterValidator attaches a validator to an event type.  Each published event of exactly that type is passed
+	// to the validator before delivery.  If the validator returns an error, a *ValidationError is passed to the error
+	// handler configured via WithErrorHandler, and the event is delivered anyway unless this hub was created with
+	// WithRejectInvalid.  This catches malformed events at the hub rather than in every listener.
+	//
+	// Only one validator is kept per type, so registering another replaces it, and a nil validator removes it.  Types
+	// without a validator cost nothing extra to publish.  If eventType is nil, ErrInvalidEventType is returned.
+	RegisterValidator(eventType reflect.Type, f func(interface{}) error) error
+
+	// PublishFrom starts a goroutine that publishes each element received from ch, which must be a channel
+	// that can be received from.  Each element is routed by its own type, exactly as if passed to Publish.
+	//
+	// The goroutine exits when ch is closed or when the returned Cancel is invoked.  Cancel does not wait
+	// for an element that is currently being published.  If ch is not a receivable channel, ErrInvalidSource
+	// is returned.
+	PublishFrom(ch interface{}) (Cancel, error)
+
+	// Describe returns a snapshot of every active subscription, in the order the subscriptions were made.
+	// This is intended for diagnostics, such as an administrative endpoint that shows how a hub is wired.
+	Describe() []SubscriptionInfo
+
+	// PublishCounts returns the number of times each event type has been published over this hub's lifetime.
+	// Events are counted whether or not any listener received them, as with Stats.PublishCount.  Nil events
+	// have no type, so they are not included.  The returned map is a snapshot owned by the caller.
+	PublishCounts() map[reflect.Type]uint64
+
+	// PublishValue publishes an event that is already held in a reflect.Value.  The event is routed using
+	// v.Type(), and v is passed directly to sinks, which avoids converting it to an interface{} and back.
+	// This is useful for reflection-heavy callers.
+	//
+	// If v is invalid, has an interface kind, or cannot be converted to an interface{} because it was
+	// obtained through unexported struct fields, ErrInvalidValue is returned and nothing is published.
+	PublishValue(v reflect.Value) error
+
+	// PublishReport publishes an event exactly as Publish does, then returns a Report describing the outcome for each
+	// subscription that matched the event.  Unlike Publish, this method allocates.
+	PublishReport(e interface{}) Report
+
+	// PublishContext publishes an event exactly as Publish does, along with a context.  The context passes
+	// through any middleware, which may replace it, and then reaches observers and context-aware listeners,
+	// i.e. those with a context.Context as their second input.  Other listeners never see it.  The context
+	// is not used for cancellation, so delivery proceeds even if it is already done.
+	//
+	// Publish and the other publish methods behave as though context.Background() was passed here.
+	PublishContext(ctx context.Context, e interface{})
+
+	// SubscribeContextHandler registers a listener in the same manner as Subscribe, but requires that the
+	// listener accept the context that accompanies each event, i.e. a function or method with a context.Context
+	// input before or after the event.  Any other listener is rejected with ErrInvalidFunction.  This guards
+	// against a context-aware handler accidentally losing its context parameter during a refactor.
+	SubscribeContextHandler(l interface{}, afterCancel ...func()) (Cancel, error)
+
+	// SubscribeErr registers a function listener that returns an error, e.g. func(MyCommand) error.  The listener's
+	// inputs may take any form accepted by Subscribe, such as func(context.Context, MyCommand) error.  Subscribe
+	// itself rejects listeners with outputs, so that a listener's error is never dropped by accident.
+	//
+	// A returned error fails the delivery.  PublishGroup returns the first such error, and PublishReport records it
+	// as Errored.  Otherwise, the error is passed to the handler set by WithErrorHandler, or logged if there is none.
+	SubscribeErr(l interface{}, afterCancel ...func()) (Cancel, error)
+
+	// PublishBatch publishes each of the given events in order, exactly as Publish does, and returns once all of
+	// them have been delivered.  Listeners that declare a Remaining input receive the number of events in the
+	// batch that follow the one being delivered, which allows them to report progress.
+	PublishBatch(events []interface{})
+
+	// PublishAck publishes an event exactly as Publish does, then waits until every channel listener that
+	// received it using WithAck has acknowledged it via Subscription.Ack.  If the timeout elapses first,
+	// ErrTimeout is returned.  If a subscription is cancelled before acknowledging the event, ErrNotAcknowledged
+	// is returned.  Listeners that do not use WithAck are not waited on.
+	//
+	// PublishAck does not retry.  Callers that need at-least-once delivery can publish again when an error is returned.
+	PublishAck(e interface{}, timeout time.Duration) error
+
+	// SubscribeKey registers a listener for events whose routing key, as computed by the function passed to
+	// WithKeyFunc, equals key.  The listener may be any listener accepted by Subscribe.  Events with a matching key
+	// whose type cannot be passed to the listener are skipped for that listener.
+	//
+	// If this hub was not created with WithKeyFunc, ErrNoKeyFunc is returned.  If key is nil or not comparable,
+	// ErrInvalidKey is returned.
+	SubscribeKey(key interface{}, l interface{}, afterCancel ...func()) (Cancel, error)
+
+	// PublishGroup delivers an event to each matching listener concurrently, in the manner of an errgroup, and
+	// waits for every listener to return.  A listener fails by returning an error, if it was subscribed with
+	// SubscribeErr, or by panicking.  Sinks that report errors, such as a writer subscribed with SubscribeWriter, fail in the same
+	// way.  The first failure is returned, with a panic converted to an error as by a *PanicError, and it cancels
+	// the context passed to any listener that accepts a context.Context, e.g. func(MyCommand, context.Context).
+	// That context is derived from ctx.  Errors returned by listeners are not passed to the hub's error handler.
+	//
+	// Otherwise, the event is published exactly as PublishContext would publish it.  Registration, validation,
+	// middleware, aliases, and parent or child hubs created with NewChild all apply, and so does Pause.  An event
+	// held by Pause is delivered as Publish would deliver it once the hub resumes, so PublishGroup returns nil
+	// without waiting for it.  An event rejected under WithRejectInvalid returns its *ValidationError rather than
+	// passing it to the error handler.  If no listener matches the event, it is unhandled and nil is returned.
+	//
+	// This hub's PanicPolicy and delivery order do not apply, since every delivery runs on its own goroutine and
+	// panics are always recovered.  For the same reason, an interceptor's veto only prevents delivery to the
+	// subscription it is attached to.
+	PublishGroup(ctx context.Context, e interface{}) error
+
+	// CancelType cancels every subscription for the given event type in a single operation, returning
+	// the number of subscriptions cancelled.  Any afterCancel closures are invoked, and the Cancel closures
+	// for those subscriptions become no-ops.  Subscriptions made with SubscribeKey are not affected.
+	CancelType(eventType reflect.Type) int
+
+	// SubscribeBatch registers a listener that receives events in batches.  The listener must be a function that takes
+	// exactly one slice, e.g. func([]MyEvent), and has no outputs.  Events of the slice's element type are accumulated,
+	// and the listener is invoked once maxSize events have arrived or maxWait has elapsed since the first event of the
+	// current batch, whichever happens first.  A maxWait of 0 or less flushes batches only when they are full.
+	//
+	// Batches are delivered serially on a goroutine dedicated to the subscription, and a publish blocks while the
+	// listener is handling a batch.  Cancellation, including via Close, flushes any partial batch and then runs the
+	// afterCancel closures on that goroutine.  Cancellation does not wait for the flush, so a listener may safely
+	// cancel its own subscription.  A panicking batch listener is recovered and logged.
+	//
+	// If maxSize is less than 1, ErrInvalidBatchSize is returned.
+	SubscribeBatch(l interface{}, maxSize int, maxWait time.Duration, afterCancel ...func()) (Cancel, error)
+
+	// Freeze marks the end of a "configure then run" lifecycle, in which all subscriptions are made at startup.
+	// Once frozen, every attempt to subscribe fails with ErrFrozen.  Cancellation is still permitted.
+	//
+	// Publishing is unaffected.  The lock-free read path used by Publish is already a single uncontended atomic
+	// load, and it remains so while frozen, which keeps Unfreeze safe to call concurrently with publishes.
+	Freeze()
+
+	// Unfreeze reverses Freeze, allowing subscriptions again.  This is primarily useful in tests.
+	Unfreeze()
+
+	// Pause suspends delivery, e.g. for a maintenance window.  Unlike Close, pausing is reversible, and subscribing
+	// is unaffected.  While paused, published events are buffered or dropped according to WithPausePolicy, and no
+	// listener or unhandled hook sees them.  Publish methods that report an outcome, such as PublishOK, report
+	// that the event was not handled, and PublishGroup returns nil without waiting for the event to be delivered.
+	// Pausing a paused hub has no effect.
+	//
+	// With the default PauseBuffer policy, each buffered event is retained in memory until Resume, along with
+	// anything it references, up to the capacity set by WithPauseCapacity.  A long pause under heavy publishing can
+	// therefore hold a significant amount of memory, and events beyond the capacity are dropped.  Dropped events are
+	// counted in Stats.
+	Pause()
+
+	// Resume ends a pause, delivering any buffered events on the calling goroutine before returning.  Buffered events
+	// are delivered in the order they were published, and are routed to the subscriptions that exist at the time of
+	// delivery.  Events published while Resume is delivering are buffered behind the others, so order is preserved.
+	// Resuming a hub that is not paused has no effect.
+	Resume()
+}
+
 // New creates a hub for both publish and subscribe.  The returned implementation is optimized around
 // publishes occurring much more often than subscribes.  The typical expected use case is that subscribes
 // happen once, near application startup, and publishes happen throughout an application's lifetime.
-func New() Interface {
-	return new(hub)
+func New(options ...Option) Dispatcher {
+	return newHub(new(cowRegistry), options)
+}
+
+// NewConcurrent constructs a hub whose subscriptions are stored in a sync.Map of per-type buckets
+// rather than in a single copy-on-write map.  The returned Dispatcher behaves exactly like one from New.
+//
+// New is the right choice for most applications.  Publishing with New costs one atomic load and a plain
+// map lookup, but subscribing to an event type that has no subscriptions copies the whole map, which
+// becomes expensive when a hub sees constant churn across many thousands of event types.  NewConcurrent
+// makes that case O(1), and deletes a type's bucket as soon as it becomes empty.  Publishing then looks up
+// buckets in a sync.Map, which is comparable to New for a stable set of event types but can be slower while
+// routes are being added and removed.  Subscribe and Cancel for a type that already has subscribers cost the
+// same with either constructor.
+//
+// The same cost applies to the burst of subscriptions made at startup, since each new event type copies the
+// map.  Applications that register hundreds of event types at once can use NewConcurrent to make that burst
+// linear rather than quadratic, or can size the copies made by New with WithInitialBuckets.
+func NewConcurrent(options ...Option) Dispatcher {
+	return newHub(new(concurrentRegistry), options)
+}
+
+func newHub(subscriptions registry, options []Option) *hub {
+	h := &hub{
+		subscriptions: subscriptions,
+	}
+
+	for _, o := range options {
+		o(h)
+	}
+
+	return h
 }
 
 // Must panics if err is not nil.  This function can be used to wrap Subscribe to panic instead of
@@ -67,48 +539,711 @@ func Must(c Cancel, err error) Cancel {
 
 // hub is the internal synchronous Dispatcher implementation
 type hub struct {
+	counters counters
+
 	subscribeLock sync.Mutex
-	subscriptions atomic.Value
+	subscriptions registry
+
+	// presence lets publishes skip routing for event types without subscriptions.  it is updated
+	// while holding subscribeLock.
+	presence presence
+
+	// closed indicates that Close has been called.  it is guarded by subscribeLock.
+	closed bool
+
+	// frozen indicates that Freeze has been called more recently than Unfreeze.  it is guarded by subscribeLock.
+	frozen bool
+
+	// unbind is closed to release the goroutine started by BindContext.  it is guarded by subscribeLock.
+	unbind chan struct{}
+
+	// typeNames holds the names registered via RegisterEventTypeNamed.  it is guarded by subscribeLock.
+	typeNames map[string]reflect.Type
+
+	// sticky holds the most recent event of each type passed to PublishSticky.  it is guarded by subscribeLock.
+	sticky map[reflect.Type]message
+
+	// implementations holds the registry maintained by RegisterImplementation.  writes are guarded by subscribeLock.
+	implementations atomic.Value
+
+	// aliases holds the map maintained by Alias.  writes are guarded by subscribeLock.
+	aliases atomic.Value
+
+	// registered holds the set of types maintained by RegisterEventType, and unregisteredPolicy is how
+	// publishes of other types are handled.  writes to registered are guarded by subscribeLock.
+	registered         atomic.Value
+	unregisteredPolicy UnregisteredPolicy
+
+	// validators holds the map maintained by RegisterValidator.  writes are guarded by subscribeLock.
+	validators atomic.Value
+
+	// rejectInvalid prevents the delivery of events that fail validation.  see WithRejectInvalid.
+	rejectInvalid bool
+
+	// subscribed is closed and reset each time a sink is added, waking any goroutines
+	// in WaitForSubscriber.  it is guarded by subscribeLock and created lazily.
+	subscribed chan struct{}
+
+	panicPolicy PanicPolicy
+	logger      *log.Logger
+	onUnhandled func(interface{})
+	onError     func(error)
+	dedup       bool
+
+	// noDuplicates rejects duplicate subscriptions.  see WithNoDuplicates.
+	noDuplicates bool
+
+	channelSendTimeout time.Duration
+
+	// closedChannelPolicy determines how sends to channels closed out from under their subscriptions are handled
+	closedChannelPolicy ClosedChannelPolicy
+
+	defensiveCopy bool
+	copyDepth     int
+	consumers     *sync.WaitGroup
+
+	// channelRefs counts the subscriptions that close each channel.  see WithCloseOnCancel.
+	channelRefs channelRefs
+
+	// mailboxes tracks the goroutines of subscriptions made with WithMailbox, and drainTimeout bounds
+	// how long each one drains after cancellation.  see WithDrainOnCancel.
+	mailboxes    sync.WaitGroup
+	drainTimeout time.Duration
+
+	observer      Observer
+	slowThreshold time.Duration
+	auditLog      func(AuditEntry)
+	order         Order
+	shutdownOrder Order
+
+	// kindRanks holds the position of each distinct kind given to WithSinkKindOrder.  it is nil if delivery
+	// is not grouped by kind.
+	kindRanks map[SinkKind]int
+
+	// parent and children link this hub into a hierarchy created by NewChild.  parent holds a *hub,
+	// and children holds a copy-on-write []*hub whose writes are guarded by subscribeLock.
+	parent       atomic.Value
+	children     atomic.Value
+	ignoreParent bool
+
+	// middleware wraps the delivery of each published event
+	middleware []Middleware
+
+	// tracer, if set, starts spans for each published event and each delivery to a sink
+	tracer Tracer
+
+	// keyFunc computes routing keys for events when keyed routing is used
+	keyFunc func(interface{}) interface{}
+
+	// groupTurns holds a *uint64 for each consumer group, counting the events the group has received
+	groupTurns sync.Map
+
+	// publishCounts holds a *uint64 for each event type that has been published
+	publishCounts sync.Map
+
+	// strategy and typeStrategies determine how Publish delivers events.  typeStrategies is only
+	// written by options, so it needs no lock.
+	strategy       Strategy
+	typeStrategies map[reflect.Type]Strategy
+
+	pause pauseState
+
+	workers       int
+	queueCapacity int
+	dropWhenFull  bool
+	asyncOnce     sync.Once
+	jobs          *queue
+
+	// flushLock serializes Flush.  the sentinels of concurrent flushes would otherwise interleave in the
+	// queue, leaving each worker parked on a different barrier that can never complete.
+	flushLock sync.Mutex
 }
 
-func (h *hub) load() subscriptions {
-	v, _ := h.subscriptions.Load().(subscriptions)
-	return v
+func (h *hub) Publish(e interface{}) {
+	eventType := reflect.TypeOf(e)
+	if h.strategyFor(eventType) == Async {
+		h.publishAsync(e, false)
+		return
+	}
+
+	h.publish(eventType, e, Meta{}, nil)
 }
 
-func (h *hub) store(new subscriptions) {
-	h.subscriptions.Store(new)
+func (h *hub) PublishOrElse(e interface{}, fallback func(interface{})) {
+	h.publish(reflect.TypeOf(e), e, Meta{}, fallback)
 }
 
-func (h *hub) Publish(e interface{}) {
-	h.load().publish(e)
+func (h *hub) PublishOK(e interface{}) bool {
+	return h.publish(reflect.TypeOf(e), e, Meta{}, nil)
+}
+
+func (h *hub) PublishMeta(e interface{}, meta Meta) {
+	if meta.Time.IsZero() {
+		meta.Time = time.Now()
+	}
+
+	h.publish(reflect.TypeOf(e), e, meta, nil)
+}
+
+func (h *hub) PublishSticky(e interface{}) {
+	eventType := reflect.TypeOf(e)
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: reflect.ValueOf(e), sticky: eventType != nil}, nil)
+}
+
+// retain stores a message as the sticky event for its type.  it is called by propagate, so that only events
+// which pass this hub's registration and validation checks, Pause, and middleware are retained.
+func (h *hub) retain(eventType reflect.Type, m message) {
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	if h.sticky == nil {
+		h.sticky = make(map[reflect.Type]message)
+	}
+
+	h.sticky[eventType] = message{value: m.value}
+}
+
+func (h *hub) PublishValue(v reflect.Value) error {
+	if !v.IsValid() || v.Kind() == reflect.Interface || !v.CanInterface() {
+		return ErrInvalidValue
+	}
+
+	eventType := v.Type()
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: v}, nil)
+	return nil
+}
+
+func (h *hub) PublishTyped(eventType reflect.Type, e interface{}) {
+	h.publish(eventType, e, Meta{}, nil)
+}
+
+// publish is the common implementation for the various publish methods.  It routes the event using
+// the given type, and returns true if the event matched at least one sink.
+func (h *hub) publish(eventType reflect.Type, e interface{}, meta Meta, fallback func(interface{})) bool {
+	h.countPublish(eventType)
+	return h.dispatch(eventType, message{value: reflect.ValueOf(e), meta: meta}, fallback)
+}
+
+// dispatch routes a message to the sinks for the given event type and to any catch-all sinks.
+// If nothing receives the message, its event is passed to the unhandled hooks.
+func (h *hub) dispatch(eventType reflect.Type, m message, fallback func(interface{})) bool {
+	if !h.known(eventType) || !h.valid(eventType, m) || h.held(eventType, m, fallback) {
+		return false
+	}
+
+	if !h.intercept(0, eventType, m) {
+		h.unhandled(m.event(), fallback)
+		return false
+	}
+
+	return true
+}
+
+// propagate delivers a message to this hub's own sinks, then forwards it through any hierarchy
+// created by NewChild.  It returns true if the message matched at least one sink in any hub.
+func (h *hub) propagate(eventType reflect.Type, m message) bool {
+	if m.sticky {
+		// only the hub the event was published to retains it
+		h.retain(eventType, m)
+		m.sticky = false
+	}
+
+	// a nil event has no type, and goes only to catch-all sinks
+	var fixed [2][]*Subscription
+	buckets, count := h.route(fixed[:0], eventType, m)
+	if count > 0 {
+		h.deliver(m, buckets...)
+	}
+
+	aliased := h.routeAlias(eventType, m)
+	return h.forward(eventType, m) || aliased || count > 0
+}
+
+// unhandled dispatches an event that matched no sinks to either the given fallback or,
+// if fallback is nil, to the hub's unhandled hook
+func (h *hub) unhandled(e interface{}, fallback func(interface{})) {
+	if fallback == nil {
+		fallback = h.onUnhandled
+	}
+
+	if fallback != nil {
+		fallback(e)
+	}
+}
+
+// deliver sends an event to each subscription in the given buckets, honoring this hub's PanicPolicy.
+// Buckets are visited in order, and the subscriptions within each bucket are visited in this hub's Order.
+func (h *hub) deliver(m message, buckets ...[]*Subscription) {
+	var (
+		first   interface{}
+		repanic bool
+	)
+
+	for _, sinks := range buckets {
+		var chosen map[string]*Subscription
+		for i := range sinks {
+			s := sinks[i]
+			if h.order == LIFO {
+				s = sinks[len(sinks)-1-i]
+			}
+
+			if s == m.except || !s.Enabled() || !h.turn(s, sinks, m.except, &chosen) {
+				m.track(s).record(Skipped, nil)
+				continue
+			}
+
+			admitted, last := s.admit()
+			if !admitted {
+				m.track(s).record(Skipped, nil)
+				continue
+			}
+
+			sm := h.copyOf(m).track(s)
+			if m.group != nil {
+				h.deliverConcurrently(s, sm, last)
+				continue
+			} else if last {
+				// the subscription has reached its limit.  deferring the cancellation lets this delivery finish
+				// first, even if the listener panics, and any later publishes are already refused by admit.
+				defer s.Cancel()
+			}
+
+			if h.panicPolicy == Propagate && h.observer.OnDelivery == nil {
+				if !h.sendTo(s, sm) {
+					// an interceptor vetoed the event for the rest of this bucket
+					sm.record(Vetoed, nil)
+					break
+				}
+
+				sm.record(Delivered, nil)
+				continue
+			}
+
+			proceed, r, panicked := h.trySend(s, sm)
+			if !proceed {
+				sm.record(Vetoed, nil)
+				break
+			} else if !panicked {
+				h.delivered(s, nil)
+				sm.record(Delivered, nil)
+				continue
+			}
+
+			err := panicError(r)
+			h.delivered(s, err)
+			sm.record(Errored, err)
+			switch h.panicPolicy {
+			case Propagate:
+				panic(r)
+
+			case RecoverAndLog:
+				h.logf("listener for %s panicked: %v", s.eventType, r)
+
+			case Repanic:
+				if !repanic {
+					first, repanic = r, true
+				}
+			}
+		}
+	}
+
+	if repanic {
+		panic(first)
+	}
+}
+
+// copyOf returns the message to send to a single sink, which carries a copy of the event if
+// this hub was configured with WithDefensiveCopy
+func (h *hub) copyOf(m message) message {
+	if h.defensiveCopy {
+		m.value = copyEvent(m.value, h.copyDepth)
+	}
+
+	return m
+}
+
+// handleError passes an error that occurred during delivery to this hub's error handler, if any
+func (h *hub) handleError(err error) {
+	if h.onError != nil {
+		h.onError(err)
+	}
+}
+
+// logf writes a diagnostic message to this hub's logger
+func (h *hub) logf(format string, args ...interface{}) {
+	if h.logger != nil {
+		h.logger.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+func (h *hub) SubscribeErr(l interface{}, afterCancel ...func()) (Cancel, error) {
+	eventType, s, err := newErrSink(l)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	return h.registerCancel(eventType, s, listenerKey(l), afterCancel...)
 }
 
 func (h *hub) Subscribe(l interface{}, afterCancel ...func()) (Cancel, error) {
+	sub, err := h.SubscribeWith(l, WithAfterCancel(afterCancel...))
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.cancelFunc(), nil
+}
+
+func (h *hub) SubscribeWith(l interface{}, options ...SubscribeOption) (*Subscription, error) {
 	eventType, s, err := newSink(l)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	return h.register(eventType, s, listenerKey(l), options...)
+}
+
+func (h *hub) SubscribeAfter(n int, l interface{}, afterCancel ...func()) (Cancel, error) {
+	sub, err := h.SubscribeWith(l, WithSkip(n-1), WithAfterCancel(afterCancel...))
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.cancelFunc(), nil
+}
+
+func (h *hub) SubscribeMethod(receiver interface{}, methodName string, afterCancel ...func()) (Cancel, error) {
+	eventType, s, err := newNamedMethodSink(receiver, methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	if receiverKey := listenerKey(receiver); receiverKey != nil {
+		key = methodKey{receiver: receiverKey, name: methodName}
+	}
+
+	return h.registerCancel(eventType, s, key, afterCancel...)
+}
+
+func (h *hub) SubscribeAll(l func(interface{}), afterCancel ...func()) (Cancel, error) {
+	if l == nil {
+		return nil, ErrInvalidListener
+	}
+
+	return h.registerCancel(anyType, &sinkAll{f: l}, listenerKey(l), afterCancel...)
+}
+
+func (h *hub) SubscribeSink(eventType reflect.Type, s Sink, afterCancel ...func()) (Cancel, error) {
+	if eventType == nil {
+		return nil, ErrInvalidEventType
+	}
+
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, ErrInvalidListener
+	}
+
+	return h.registerCancel(eventType, &sinkCustom{s: s}, listenerKey(s), afterCancel...)
+}
+
+// register adds a sink for the given event type and returns the Subscription that represents it.
+// The key is the identity of the listener, and can be nil if the identity is unknown.  If this hub
+// was created with WithDedup and an existing subscription has the same key, that subscription is
+// returned instead and the given options are ignored.
+//
+// If this hub has been closed, ErrClosed is returned.
+func (h *hub) register(eventType reflect.Type, s sink, key interface{}, options ...SubscribeOption) (*Subscription, error) {
+	sub := &Subscription{
+		id:        atomic.AddUint64(&h.counters.ids, 1),
+		hub:       h,
+		eventType: eventType,
+		route:     eventType,
+		sink:      s,
+		key:       key,
+	}
+
+	sub.cancel = func() {
+		sub.Cancel()
+	}
+
+	for _, o := range options {
+		o(sub)
+	}
+
+	var mb *sinkMailbox
+	if sub.mailbox > 0 {
+		mb = newSinkMailbox(sub)
+	}
+
+	sub.rank = h.kindRank(sub.sink)
+
+	added, sticky, err := h.insert(sub)
+	if err != nil || added != sub {
+		return added, err
+	}
+
+	if mb != nil {
+		mb.start()
+	}
+
+	h.countSubscribe(sub)
+	h.audit(AuditSubscribe, sub)
+	for _, m := range sticky {
+		h.deliver(m, []*Subscription{sub})
+	}
+
+	return sub, nil
+}
+
+// insert performs the locked portion of register.  The returned subscription is either sub itself
+// or an existing duplicate of sub.  If sub was added, the messages of any sticky events it should
+// receive are returned as well.
+func (h *hub) insert(sub *Subscription) (*Subscription, []message, error) {
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	if h.closed {
+		return nil, nil, ErrClosed
+	} else if h.frozen {
+		return nil, nil, ErrFrozen
+	}
+
+	if h.dedup || h.noDuplicates {
+		if existing := find(h.subscriptions, sub.route, sub.key); existing != nil {
+			if h.noDuplicates {
+				return nil, nil, ErrDuplicateListener
+			}
+
+			return existing, nil, nil
+		}
+	}
+
+	if len(h.subscriptions.sinks(sub.route)) == 0 {
+		h.presence.add(sub.route)
+	}
+
+	h.subscriptions.add(sub)
+	if sub.closes.IsValid() {
+		// counted only once sub is certain to be added, since a subscription that is never added is never cancelled
+		h.channelRefs.acquire(sub.closes)
+	}
+
+	if h.subscribed != nil {
+		close(h.subscribed)
+		h.subscribed = nil
+	}
+
+	if sub.stickyAll {
+		sticky := make([]message, 0, len(h.sticky))
+		for _, m := range h.sticky {
+			sticky = append(sticky, m)
+		}
+
+		return sub, sticky, nil
+	}
+
+	if sub.route != interface{}(sub.eventType) {
+		// keyed subscriptions do not receive sticky events
+		return sub, nil, nil
+	}
+
+	if sticky, ok := h.sticky[sub.eventType]; ok {
+		return sub, []message{sticky}, nil
+	}
+
+	return sub, nil, nil
+}
+
+// registerCancel is a convenience for subscribe methods that return a Cancel rather than a Subscription
+func (h *hub) registerCancel(eventType reflect.Type, s sink, key interface{}, afterCancel ...func()) (Cancel, error) {
+	sub, err := h.register(eventType, s, key, WithAfterCancel(afterCancel...))
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.cancelFunc(), nil
+}
+
+func (h *hub) Close() {
 	h.subscribeLock.Lock()
-	h.store(h.load().add(eventType, s))
+	if h.closed {
+		h.subscribeLock.Unlock()
+		return
+	}
+
+	h.closed = true
+	removed := h.subscriptions.removeAll()
+	h.presence.clear()
+	if h.unbind != nil {
+		close(h.unbind)
+		h.unbind = nil
+	}
+
 	h.subscribeLock.Unlock()
 
-	return h.cancel(eventType, s, afterCancel...), nil
+	h.stopWorkers()
+
+	if p := h.loadParent(); p != nil {
+		h.parent.Store((*hub)(nil))
+		p.detach(h)
+	}
+
+	h.shutdownOrder.sort(removed)
+	for _, sub := range removed {
+		sub.release()
+	}
 }
 
-// cancel creates a Cancel closure that will remove the given tuple from the subscriptions
-func (h *hub) cancel(eventType reflect.Type, s sink, afterCancel ...func()) Cancel {
-	var once sync.Once
-	return func() {
-		once.Do(func() {
+func (h *hub) BindContext(ctx context.Context) {
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	if h.unbind != nil {
+		close(h.unbind)
+	}
+
+	unbind := make(chan struct{})
+	h.unbind = unbind
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// the binding may have been replaced concurrently with ctx ending
 			h.subscribeLock.Lock()
-			h.store(h.load().remove(eventType, s))
+			current := h.unbind == unbind
 			h.subscribeLock.Unlock()
 
-			for _, f := range afterCancel {
-				f()
+			if current {
+				h.Close()
 			}
-		})
+
+		case <-unbind:
+		}
+	}()
+}
+
+func (h *hub) CloseAndDrain(timeout time.Duration) error {
+	h.Close()
+
+	drained := make(chan struct{})
+	go func() {
+		h.mailboxes.Wait()
+		if h.consumers != nil {
+			h.consumers.Wait()
+		}
+
+		close(drained)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+		return nil
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
+
+func (h *hub) WaitForSubscriber(ctx context.Context, eventType reflect.Type) error {
+	if eventType == nil {
+		return ErrInvalidEventType
+	}
+
+	for {
+		h.subscribeLock.Lock()
+		if len(h.subscriptions.sinks(eventType)) > 0 {
+			h.subscribeLock.Unlock()
+			return nil
+		}
+
+		if h.subscribed == nil {
+			h.subscribed = make(chan struct{})
+		}
+
+		subscribed := h.subscribed
+		h.subscribeLock.Unlock()
+
+		select {
+		case <-subscribed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (h *hub) Freeze() {
+	h.subscribeLock.Lock()
+	h.frozen = true
+	h.subscribeLock.Unlock()
+}
+
+func (h *hub) Unfreeze() {
+	h.subscribeLock.Lock()
+	h.frozen = false
+	h.subscribeLock.Unlock()
+}
+
+func (h *hub) CancelType(eventType reflect.Type) int {
+	if eventType == nil {
+		return 0
 	}
+
+	// emptying the bucket removes every subscription at once
+	h.subscribeLock.Lock()
+	removed := h.subscriptions.removeRoute(eventType)
+	if len(removed) > 0 {
+		h.presence.remove(eventType)
+	}
+
+	h.subscribeLock.Unlock()
+
+	h.shutdownOrder.sort(removed)
+
+	for _, sub := range removed {
+		sub.release()
+	}
+
+	return len(removed)
+}
+
+// remove deregisters the given subscription, returning true if it was present
+func (h *hub) remove(sub *Subscription) bool {
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	if !h.subscriptions.remove(sub) {
+		return false
+	}
+
+	if len(h.subscriptions.sinks(sub.route)) == 0 {
+		h.presence.remove(sub.route)
+	}
+
+	return true
 }
@@ -2,11 +2,15 @@ package hub
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +27,52 @@ type BadListener struct {
 func (bl BadListener) Invalid(a, b int) {
 }
 
+type MultiListener struct {
+	events  []TestEvent
+	strings []string
+}
+
+func (ml *MultiListener) OnEvent(e TestEvent) {
+	ml.events = append(ml.events, e)
+}
+
+func (ml *MultiListener) OnString(s string) {
+	ml.strings = append(ml.strings, s)
+}
+
+func (ml *MultiListener) Invalid(a, b int) {
+}
+
+func (ml *MultiListener) Reader(io.Reader) {
+}
+
+// EventListener is a type with a single method, used to compare the ways a method can become a listener
+type EventListener struct {
+	events *[]TestEvent
+}
+
+func (el EventListener) OnEvent(e TestEvent) {
+	*el.events = append(*el.events, e)
+}
+
+// ReaderListener is a type with a single method whose input is an interface
+type ReaderListener struct{}
+
+func (ReaderListener) OnReader(io.Reader) {}
+
+// TestEventHandler is implemented by EventListener
+type TestEventHandler interface {
+	OnEvent(TestEvent)
+}
+
+type recordingSink struct {
+	events []interface{}
+}
+
+func (rs *recordingSink) Send(e interface{}) {
+	rs.events = append(rs.events, e)
+}
+
 func testHubPublishSubscribe(t *testing.T) {
 	var (
 		assert  = assert.New(t)
@@ -140,9 +190,819 @@ func testHubInvalidSubscribe(t *testing.T) {
 	}
 }
 
+func testHubPublishOrElse(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		hooked   []interface{}
+		fellBack []interface{}
+		received []int
+
+		h = New(WithUnhandled(func(e interface{}) { hooked = append(hooked, e) }))
+	)
+
+	_, err := h.Subscribe(func(e int) { received = append(received, e) })
+	require.NoError(err)
+
+	fallback := func(e interface{}) { fellBack = append(fellBack, e) }
+	h.PublishOrElse(1, fallback)
+	h.PublishOrElse("unhandled", fallback)
+	h.PublishOrElse("also unhandled", nil)
+	h.Publish("published")
+
+	assert.Equal([]int{1}, received)
+	assert.Equal([]interface{}{"unhandled"}, fellBack)
+	assert.Equal([]interface{}{"also unhandled", "published"}, hooked)
+}
+
+func testHubSubscribeMethod(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		ml = new(MultiListener)
+		h  = New()
+	)
+
+	cancel, err := h.SubscribeMethod(ml, "OnEvent")
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish("not subscribed")
+	cancel()
+	h.Publish(TestEvent{Value: 2})
+
+	assert.Equal([]TestEvent{{Value: 1}}, ml.events)
+	assert.Empty(ml.strings)
+
+	testData := []struct {
+		receiver   interface{}
+		methodName string
+		expected   error
+	}{
+		{ml, "Missing", ErrNoSuchMethod},
+		{ml, "invalid", ErrNoSuchMethod},
+		{MultiListener{}, "OnEvent", ErrNoSuchMethod}, // pointer receiver methods aren't in the value's method set
+		{ml, "Invalid", ErrInvalidFunction},
+		{ml, "Reader", ErrInvalidEventType},
+		{nil, "OnEvent", ErrInvalidListener},
+	}
+
+	for _, record := range testData {
+		cancel, err := h.SubscribeMethod(record.receiver, record.methodName)
+		assert.Equal(record.expected, err, record.methodName)
+		assert.Nil(cancel)
+	}
+}
+
+func testHubMethodListeners(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		events []TestEvent
+		el     = EventListener{events: &events}
+	)
+
+	var handler TestEventHandler = el
+
+	// bound method values, method values taken from an interface, single-method types, and SubscribeMethod
+	// are equivalent ways to subscribe a method
+	sources := map[string]func(Dispatcher) (Cancel, error){
+		"MethodValue":          func(h Dispatcher) (Cancel, error) { return h.Subscribe(el.OnEvent) },
+		"InterfaceMethodValue": func(h Dispatcher) (Cancel, error) { return h.Subscribe(handler.OnEvent) },
+		"SingleMethodType":     func(h Dispatcher) (Cancel, error) { return h.Subscribe(el) },
+		"SubscribeMethod":      func(h Dispatcher) (Cancel, error) { return h.SubscribeMethod(el, "OnEvent") },
+	}
+
+	for name, subscribe := range sources {
+		events = nil
+		h := New()
+		cancel, err := subscribe(h)
+		require.NoError(err, name)
+
+		h.Publish(TestEvent{Value: 1})
+		cancel()
+		h.Publish(TestEvent{Value: 2})
+		assert.Equal([]TestEvent{{Value: 1}}, events, name)
+	}
+
+	// interface event types are rejected the same way by each
+	var (
+		h  = New()
+		rl = ReaderListener{}
+	)
+
+	for name, l := range map[string]interface{}{"MethodValue": rl.OnReader, "SingleMethodType": rl} {
+		cancel, err := h.Subscribe(l)
+		assert.Equal(ErrInvalidEventType, err, name)
+		assert.Nil(cancel, name)
+	}
+
+	cancel, err := h.SubscribeMethod(rl, "OnReader")
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	// a method expression takes its receiver as the first input, which the hub cannot supply
+	cancel, err = h.Subscribe(EventListener.OnEvent)
+	assert.Equal(ErrInvalidFunction, err)
+	assert.Nil(cancel)
+}
+
+func testHubWaitForSubscriber(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		received = make(chan int, 1)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(context.Canceled, h.WaitForSubscriber(ctx, reflect.TypeOf(0)))
+	assert.Equal(ErrInvalidEventType, h.WaitForSubscriber(context.Background(), nil))
+
+	go func() {
+		// an unrelated subscription should not satisfy the wait
+		Must(h.Subscribe(func(string) {}))
+		Must(h.Subscribe(received))
+	}()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(h.WaitForSubscriber(ctx, reflect.TypeOf(0)))
+
+	h.Publish(123)
+	assert.Equal(123, <-received)
+
+	// an existing subscriber satisfies the wait immediately
+	assert.NoError(h.WaitForSubscriber(ctx, reflect.TypeOf(0)))
+}
+
+func testHubSubscribeAll(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		order     []string
+		unhandled int
+		h         = New(WithUnhandled(func(interface{}) { unhandled++ }))
+	)
+
+	cancel, err := h.SubscribeAll(func(e interface{}) { order = append(order, "all") })
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	_, err = h.Subscribe(func(int) { order = append(order, "int") })
+	require.NoError(err)
+
+	h.Publish(1)
+	h.Publish("catch-all only")
+	assert.Equal([]string{"int", "all", "all"}, order)
+	assert.Zero(unhandled)
+
+	cancel()
+	h.Publish("unhandled")
+	assert.Equal(1, unhandled)
+
+	cancel, err = h.SubscribeAll(nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
+
+func testHubClose(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		afterCancel int
+		unhandled   int
+		h           = New(WithUnhandled(func(interface{}) { unhandled++ }))
+	)
+
+	sub, err := h.SubscribeWith(func(int) {}, WithAfterCancel(func() { afterCancel++ }))
+	require.NoError(err)
+
+	_, err = h.SubscribeAll(func(interface{}) {}, func() { afterCancel++ })
+	require.NoError(err)
+
+	h.Close()
+	assert.Equal(2, afterCancel)
+	assert.False(sub.Active())
+	assert.False(sub.Cancel())
+
+	h.Publish(1)
+	assert.Equal(1, unhandled)
+
+	// idempotent
+	h.Close()
+	assert.Equal(2, afterCancel)
+
+	cancel, err := h.Subscribe(func(int) {})
+	assert.Equal(ErrClosed, err)
+	assert.Nil(cancel)
+
+	assert.NotPanics(func() { h.Tee(New())() })
+}
+
+func testHubCloseAndDrain(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		consumers = new(sync.WaitGroup)
+		h         = New(WithConsumerWaitGroup(consumers))
+		c         = make(chan int, 10)
+		received  []int
+	)
+
+	_, err := h.SubscribeWith(c, WithCloseOnCancel())
+	require.NoError(err)
+
+	consumers.Add(1)
+	go func() {
+		defer consumers.Done()
+		for e := range c {
+			received = append(received, e)
+		}
+	}()
+
+	h.Publish(1)
+	h.Publish(2)
+	assert.NoError(h.CloseAndDrain(5 * time.Second))
+	assert.Equal([]int{1, 2}, received)
+
+	// a consumer that never finishes
+	consumers.Add(1)
+	defer consumers.Done()
+	assert.Equal(ErrTimeout, h.CloseAndDrain(10*time.Millisecond))
+
+	// without a WaitGroup there is nothing to drain
+	assert.NoError(New().CloseAndDrain(time.Second))
+}
+
+func testHubPublishNil(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		unhandled []interface{}
+		all       []interface{}
+		typed     int
+		h         = New(
+			WithUnhandled(func(e interface{}) { unhandled = append(unhandled, e) }),
+			WithPanicPolicy(Repanic),
+			WithDefensiveCopy(),
+		)
+	)
+
+	_, err := h.Subscribe(func(*TestEvent) { typed++ })
+	require.NoError(err)
+
+	assert.NotPanics(func() { h.Publish(nil) })
+	assert.Equal([]interface{}{nil}, unhandled)
+
+	_, err = h.SubscribeAll(func(e interface{}) { all = append(all, e) })
+	require.NoError(err)
+
+	assert.NotPanics(func() { h.Publish(nil) })
+	assert.Equal([]interface{}{nil}, all)
+	assert.Len(unhandled, 1)
+	assert.Zero(typed)
+
+	// a typed nil is not a nil event
+	h.Publish((*TestEvent)(nil))
+	assert.Equal(1, typed)
+	assert.Equal([]interface{}{nil, (*TestEvent)(nil)}, all)
+}
+
+func testHubPublishOK(t *testing.T) {
+	var (
+		assert    = assert.New(t)
+		unhandled int
+		h         = New(WithUnhandled(func(interface{}) { unhandled++ }))
+	)
+
+	assert.False(h.PublishOK(1))
+	assert.Equal(1, unhandled)
+
+	cancel := Must(h.Subscribe(func(int) {}))
+	assert.True(h.PublishOK(1))
+	assert.False(h.PublishOK("no subscribers"))
+	cancel()
+
+	Must(h.SubscribeAll(func(interface{}) {}))
+	assert.True(h.PublishOK(1))
+	assert.True(h.PublishOK("catch-all"))
+	assert.Equal(2, unhandled)
+}
+
+func testHubSubscribeSink(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		s = new(recordingSink)
+		h = New()
+	)
+
+	cancel, err := h.SubscribeSink(reflect.TypeOf(TestEvent{}), s)
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(2)
+	cancel()
+	h.Publish(TestEvent{Value: 3})
+	assert.Equal([]interface{}{TestEvent{Value: 1}}, s.events)
+
+	cancel, err = h.SubscribeSink(nil, s)
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	cancel, err = h.SubscribeSink(reflect.TypeOf(0), nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+
+	// interfaces must be registered first, just as with Subscribe
+	stringerType := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	cancel, err = h.SubscribeSink(stringerType, s)
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	require.NoError(h.RegisterImplementation(stringerType, reflect.TypeOf(namedEvent(""))))
+	cancel, err = h.SubscribeSink(stringerType, s)
+	require.NoError(err)
+	h.Publish(namedEvent("named"))
+	cancel()
+	assert.Equal([]interface{}{TestEvent{Value: 1}, namedEvent("named")}, s.events)
+}
+
+func testHubPublishTyped(t *testing.T) {
+	var (
+		assert    = assert.New(t)
+		received  []TestEvent
+		unhandled int
+		h         = New(WithUnhandled(func(interface{}) { unhandled++ }))
+	)
+
+	Must(h.Subscribe(func(e TestEvent) { received = append(received, e) }))
+
+	eventType := reflect.TypeOf(TestEvent{})
+	h.PublishTyped(eventType, TestEvent{Value: 1})
+	h.PublishTyped(reflect.TypeOf(""), TestEvent{Value: 2})
+	h.PublishTyped(eventType, TestEvent{Value: 3})
+
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 3}}, received)
+	assert.Equal(1, unhandled)
+}
+
+func testHubBindContext(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h      = New()
+		closed = make(chan struct{})
+	)
+
+	Must(h.Subscribe(func(int) {}, func() { close(closed) }))
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+
+	h.BindContext(first)
+	h.BindContext(second)
+
+	// the first binding was replaced
+	cancelFirst()
+	select {
+	case <-closed:
+		assert.Fail("a replaced binding should not close the hub")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancelSecond()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		assert.Fail("the hub was not closed")
+	}
+
+	_, err := h.Subscribe(func(int) {})
+	assert.Equal(ErrClosed, err)
+
+	// binding a closed hub does nothing
+	h.BindContext(context.Background())
+}
+
+func testHubPublishSticky(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+
+		early []string
+		late  []string
+		all   []interface{}
+	)
+
+	Must(h.Subscribe(func(e string) { early = append(early, e) }))
+
+	h.PublishSticky("first")
+	h.PublishSticky("second")
+	h.Publish("not sticky")
+	h.PublishSticky(nil)
+
+	Must(h.Subscribe(func(e string) { late = append(late, e) }))
+	Must(h.SubscribeAll(func(e interface{}) { all = append(all, e) }))
+
+	assert.Equal([]string{"first", "second", "not sticky"}, early)
+	assert.Equal([]string{"second"}, late)
+	assert.Empty(all)
+
+	h.Publish("third")
+	assert.Equal([]string{"second", "third"}, late)
+	assert.Equal(uint64(5), h.Stats().PublishCount)
+}
+
+func testHubPublishStickyChecks(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h = New(
+			WithUnregisteredPolicy(IgnoreUnregistered),
+			WithMiddleware(func(ctx context.Context, e interface{}, next func(context.Context)) {
+				if e != "blocked" {
+					next(ctx)
+				}
+			}),
+		)
+
+		late []interface{}
+	)
+
+	assert.NoError(h.RegisterEventType(reflect.TypeOf("")))
+
+	// events that never reach the listeners are not retained
+	h.PublishSticky("first")
+	h.PublishSticky("blocked")
+	h.PublishSticky(1)
+
+	// an event held by Pause is retained once it is delivered
+	h.Pause()
+	h.PublishSticky("held")
+	Must(h.Subscribe(func(e string) { late = append(late, e) }))
+	h.Resume()
+
+	Must(h.Subscribe(func(e string) { late = append(late, e) }))
+	Must(h.Subscribe(func(e int) { late = append(late, e) }))
+	assert.Equal([]interface{}{"first", "held", "held"}, late)
+}
+
+func testHubTypeListener(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		types []reflect.Type
+		ints  []int
+		h     = New()
+
+		l = func(t reflect.Type, e int) {
+			types = append(types, t)
+			ints = append(ints, e)
+		}
+	)
+
+	Must(h.Subscribe(l))
+	h.Publish(123)
+	assert.Equal([]reflect.Type{reflect.TypeOf(0)}, types)
+	assert.Equal([]int{123}, ints)
+
+	// the type comes first, so other second inputs are still rejected
+	_, err := h.Subscribe(func(int, reflect.Type) {})
+	assert.Equal(ErrInvalidFunction, err)
+}
+
+func testHubChannelSendTimeout(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		full     = make(chan int, 1)
+		received []int
+		h        = New(WithChannelSendTimeout(10 * time.Millisecond))
+	)
+
+	Must(h.Subscribe(full))
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+
+	h.Publish(1)
+	h.Publish(2)
+
+	// the second event times out for the full channel, but other sinks still receive it
+	assert.Equal([]int{1, 2}, received)
+	assert.Equal(1, <-full)
+	assert.Equal(uint64(1), h.Stats().DroppedCount)
+
+	h.Publish(3)
+	assert.Equal(3, <-full)
+	assert.Equal(uint64(1), h.Stats().DroppedCount)
+}
+
+func testHubPublishValue(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received []TestEvent
+		h        = New()
+	)
+
+	Must(h.Subscribe(func(e TestEvent) { received = append(received, e) }))
+
+	assert.NoError(h.PublishValue(reflect.ValueOf(TestEvent{Value: 1})))
+	assert.Equal([]TestEvent{{Value: 1}}, received)
+	assert.Equal(uint64(1), h.PublishCounts()[reflect.TypeOf(TestEvent{})])
+
+	var (
+		holder   = struct{ e interface{} }{e: TestEvent{Value: 2}}
+		readOnly = reflect.ValueOf(struct{ e TestEvent }{}).Field(0)
+	)
+
+	assert.Equal(ErrInvalidValue, h.PublishValue(reflect.Value{}))
+	assert.Equal(ErrInvalidValue, h.PublishValue(reflect.ValueOf(&holder).Elem().Field(0)))
+	assert.Equal(ErrInvalidValue, h.PublishValue(readOnly))
+	assert.Len(received, 1)
+}
+
+func testHubCancelType(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received    []int
+		strings     []string
+		afterCancel int
+		h           = New()
+	)
+
+	cancel := Must(h.Subscribe(func(e int) { received = append(received, e) }, func() { afterCancel++ }))
+	Must(h.Subscribe(func(e int) { received = append(received, e) }, func() { afterCancel++ }))
+	Must(h.Subscribe(func(e string) { strings = append(strings, e) }))
+
+	assert.Equal(2, h.CancelType(reflect.TypeOf(0)))
+	assert.Equal(2, afterCancel)
+	assert.Equal(0, h.CancelType(reflect.TypeOf(0)))
+	assert.Equal(0, h.CancelType(nil))
+
+	// the original Cancel is now a no-op
+	cancel()
+	assert.Equal(2, afterCancel)
+
+	h.Publish(1)
+	h.Publish("unaffected")
+	assert.Empty(received)
+	assert.Equal([]string{"unaffected"}, strings)
+
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+	h.Publish(2)
+	assert.Equal([]int{2}, received)
+}
+
+func testHubSubscribeAfter(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received    []int
+		afterCancel bool
+		h           = New()
+	)
+
+	cancel, err := h.SubscribeAfter(3, func(e int) { received = append(received, e) }, func() { afterCancel = true })
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	for i := 1; i <= 5; i++ {
+		h.Publish(i)
+	}
+
+	cancel()
+	h.Publish(6)
+	assert.Equal([]int{3, 4, 5}, received)
+	assert.True(afterCancel)
+
+	// values less than 2 deliver every event
+	received = nil
+	Must(h.SubscribeAfter(0, func(e int) { received = append(received, e) }))
+	h.Publish(7)
+	assert.Equal([]int{7}, received)
+
+	cancel, err = h.SubscribeAfter(2, "not a listener")
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
+
+func testHubSubscribeTypes(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received    []interface{}
+		afterCancel int
+		listener    = func(e interface{}) { received = append(received, e) }
+		h           = New()
+	)
+
+	cancel, err := h.SubscribeTypes(
+		listener,
+		[]reflect.Type{reflect.TypeOf(0), reflect.TypeOf(""), reflect.TypeOf(0)},
+		func() { afterCancel++ },
+	)
+
+	require.NoError(err)
+	require.NotNil(cancel)
+	assert.Equal(2, h.Stats().TotalSubscriptions)
+
+	h.Publish(1)
+	h.Publish("two")
+	assert.False(h.PublishOK(3.0))
+	assert.Equal([]interface{}{1, "two"}, received)
+
+	// afterCancel runs once, after every type has been removed
+	assert.Equal(1, h.CancelType(reflect.TypeOf(0)))
+	assert.Zero(afterCancel)
+	cancel()
+	assert.Equal(1, afterCancel)
+	cancel()
+	assert.Equal(1, afterCancel)
+
+	h.Publish("three")
+	assert.Equal([]interface{}{1, "two"}, received)
+
+	for _, types := range [][]reflect.Type{nil, {nil}, {reflect.TypeOf(0), reflect.TypeOf((*io.Reader)(nil)).Elem()}} {
+		cancel, err = h.SubscribeTypes(listener, types)
+		assert.Equal(ErrInvalidEventType, err)
+		assert.Nil(cancel)
+	}
+
+	cancel, err = h.SubscribeTypes(nil, []reflect.Type{reflect.TypeOf(0)})
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+
+	// nothing is left registered after a failure
+	assert.Zero(h.Stats().TotalSubscriptions)
+
+	// the hub's closure counts as cancellation
+	Must(h.SubscribeTypes(listener, []reflect.Type{reflect.TypeOf(0), reflect.TypeOf("")}, func() { afterCancel++ }))
+	h.Close()
+	assert.Equal(2, afterCancel)
+}
+
+func testHubFreeze(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received []int
+		h        = New()
+	)
+
+	cancel := Must(h.Subscribe(func(e int) { received = append(received, e) }))
+	h.Freeze()
+
+	_, err := h.Subscribe(func(string) {})
+	assert.Equal(ErrFrozen, err)
+
+	_, err = h.SubscribeAll(func(interface{}) {})
+	assert.Equal(ErrFrozen, err)
+
+	h.Publish(1)
+	assert.Equal([]int{1}, received)
+
+	// cancellation is still permitted
+	cancel()
+	h.Publish(2)
+	assert.Equal([]int{1}, received)
+
+	h.Unfreeze()
+	_, err = h.Subscribe(func(string) {})
+	assert.NoError(err)
+}
+
 func TestHub(t *testing.T) {
 	t.Run("PublishSubscribe", testHubPublishSubscribe)
 	t.Run("InvalidSubscribe", testHubInvalidSubscribe)
+	t.Run("PublishOrElse", testHubPublishOrElse)
+	t.Run("SubscribeMethod", testHubSubscribeMethod)
+	t.Run("MethodListeners", testHubMethodListeners)
+	t.Run("WaitForSubscriber", testHubWaitForSubscriber)
+	t.Run("SubscribeAll", testHubSubscribeAll)
+	t.Run("Close", testHubClose)
+	t.Run("CloseAndDrain", testHubCloseAndDrain)
+	t.Run("PublishNil", testHubPublishNil)
+	t.Run("PublishOK", testHubPublishOK)
+	t.Run("SubscribeSink", testHubSubscribeSink)
+	t.Run("PublishTyped", testHubPublishTyped)
+	t.Run("BindContext", testHubBindContext)
+	t.Run("PublishSticky", testHubPublishSticky)
+	t.Run("PublishStickyChecks", testHubPublishStickyChecks)
+	t.Run("TypeListener", testHubTypeListener)
+	t.Run("ChannelSendTimeout", testHubChannelSendTimeout)
+	t.Run("PublishValue", testHubPublishValue)
+	t.Run("CancelType", testHubCancelType)
+	t.Run("Freeze", testHubFreeze)
+	t.Run("SubscribeAfter", testHubSubscribeAfter)
+	t.Run("SubscribeTypes", testHubSubscribeTypes)
+	t.Run("SubscribeFor", testHubSubscribeFor)
+	t.Run("Unsubscribe", testHubUnsubscribe)
+}
+
+func testHubSubscribeFor(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received    []interface{}
+		afterCancel int
+		h           = New()
+	)
+
+	cancel, err := h.SubscribeFor(
+		reflect.TypeOf(TestEvent{}),
+		func(e interface{}) { received = append(received, e) },
+		func() { afterCancel++ },
+	)
+
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	h.Publish(TestEvent{Value: 1})
+	assert.False(h.PublishOK(2))
+	cancel()
+	h.Publish(TestEvent{Value: 3})
+
+	assert.Equal([]interface{}{TestEvent{Value: 1}}, received)
+	assert.Equal(1, afterCancel)
+
+	cancel, err = h.SubscribeFor(nil, func(interface{}) {})
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	cancel, err = h.SubscribeFor(reflect.TypeOf(0), nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
+
+func testHubUnsubscribe(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+
+		c           = make(chan int, 10)
+		all         []interface{}
+		afterCancel int
+		listener    = func(e interface{}) { all = append(all, e) }
+		ml          = new(MultiListener)
+	)
+
+	Must(h.Subscribe(c, func() { afterCancel++ }))
+	Must(h.Subscribe(c))
+	Must(h.SubscribeAll(listener))
+	Must(h.SubscribeMethod(ml, "OnString"))
+
+	removed, err := h.Unsubscribe(c)
+	require.NoError(err)
+	assert.True(removed)
+	assert.Equal(1, afterCancel)
+
+	removed, err = h.Unsubscribe(c)
+	require.NoError(err)
+	assert.False(removed)
+
+	removed, err = h.Unsubscribe(listener)
+	require.NoError(err)
+	assert.True(removed)
+
+	// a new bound method value is never identical to the one subscribed
+	removed, err = h.Unsubscribe(ml.OnString)
+	require.NoError(err)
+	assert.False(removed)
+
+	h.Publish(1)
+	h.Publish("one")
+	assert.Empty(c)
+	assert.Empty(all)
+	assert.Equal([]string{"one"}, ml.strings)
+
+	removed, err = h.Unsubscribe(func(int, int) {})
+	assert.Equal(ErrInvalidFunction, err)
+	assert.False(removed)
+
+	removed, err = h.Unsubscribe(func(io.Reader) {})
+	assert.Equal(ErrInvalidEventType, err)
+	assert.False(removed)
 }
 
 func TestMust(t *testing.T) {
@@ -161,3 +1021,12 @@ func TestMust(t *testing.T) {
 		Must(h.Subscribe(func(string, int) {}))
 	})
 }
+
+func TestInterface(t *testing.T) {
+	assert := assert.New(t)
+
+	// Interface is implemented outside this package, e.g. by mocks, so it must not grow
+	interfaceType := reflect.TypeOf((*Interface)(nil)).Elem()
+	assert.Equal(2, interfaceType.NumMethod())
+	assert.True(reflect.TypeOf((*Dispatcher)(nil)).Elem().Implements(interfaceType))
+}
@@ -0,0 +1,3 @@
+// Package hubtest provides fakes for testing code that depends on the hub.Publisher and
+// hub.Subscriber interfaces, without requiring a mocking library or a hand-rolled fake.
+package hubtest
@@ -0,0 +1,119 @@
+package hubtest
+
+import (
+	"sync"
+
+	"github.com/johnabass/hub"
+)
+
+// FakePublisher is a hub.Publisher that records each published event rather than routing it.
+// The zero value is ready to use.  FakePublisher instances are safe for concurrent use.
+type FakePublisher struct {
+	lock      sync.Mutex
+	published []interface{}
+}
+
+var _ hub.Publisher = (*FakePublisher)(nil)
+
+// Publish records the given event
+func (fp *FakePublisher) Publish(e interface{}) {
+	fp.lock.Lock()
+	fp.published = append(fp.published, e)
+	fp.lock.Unlock()
+}
+
+// Published returns the events published so far, in the order they were published.  The returned
+// slice is a copy owned by the caller.
+func (fp *FakePublisher) Published() []interface{} {
+	fp.lock.Lock()
+	defer fp.lock.Unlock()
+
+	if len(fp.published) == 0 {
+		return nil
+	}
+
+	published := make([]interface{}, len(fp.published))
+	copy(published, fp.published)
+	return published
+}
+
+// Reset discards all recorded events
+func (fp *FakePublisher) Reset() {
+	fp.lock.Lock()
+	fp.published = nil
+	fp.lock.Unlock()
+}
+
+// FakeSubscriber is a hub.Subscriber that records each listener passed to Subscribe.  Listeners are
+// validated and registered exactly as a real hub would, so tests can drive them with Publish.
+// FakeSubscriber instances are safe for concurrent use, and must be created with NewFakeSubscriber.
+type FakeSubscriber struct {
+	lock       sync.Mutex
+	options    []hub.Option
+	hub        hub.Dispatcher
+	subscribed []interface{}
+}
+
+var _ hub.Subscriber = (*FakeSubscriber)(nil)
+
+// NewFakeSubscriber creates a FakeSubscriber backed by a hub created with the given options
+func NewFakeSubscriber(options ...hub.Option) *FakeSubscriber {
+	return &FakeSubscriber{
+		options: options,
+		hub:     hub.New(options...),
+	}
+}
+
+// current returns the hub currently backing this fake
+func (fs *FakeSubscriber) current() hub.Dispatcher {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	return fs.hub
+}
+
+// Subscribe registers a listener.  Only listeners that were successfully subscribed are recorded.
+func (fs *FakeSubscriber) Subscribe(l interface{}, afterCancel ...func()) (hub.Cancel, error) {
+	cancel, err := fs.current().Subscribe(l, afterCancel...)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.lock.Lock()
+	fs.subscribed = append(fs.subscribed, l)
+	fs.lock.Unlock()
+
+	return cancel, nil
+}
+
+// Subscribed returns the listeners subscribed so far, in the order they were subscribed.  Listeners
+// remain in this list after they are cancelled.  The returned slice is a copy owned by the caller.
+func (fs *FakeSubscriber) Subscribed() []interface{} {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if len(fs.subscribed) == 0 {
+		return nil
+	}
+
+	subscribed := make([]interface{}, len(fs.subscribed))
+	copy(subscribed, fs.subscribed)
+	return subscribed
+}
+
+// Publish delivers an event to the subscribed listeners that have not been cancelled, returning
+// true if any listener received it
+func (fs *FakeSubscriber) Publish(e interface{}) bool {
+	return fs.current().PublishOK(e)
+}
+
+// Reset cancels all subscriptions, invoking any afterCancel closures, and discards the record of
+// subscribed listeners
+func (fs *FakeSubscriber) Reset() {
+	fs.lock.Lock()
+	old := fs.hub
+	fs.hub = hub.New(fs.options...)
+	fs.subscribed = nil
+	fs.lock.Unlock()
+
+	old.Close()
+}
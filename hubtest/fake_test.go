@@ -0,0 +1,63 @@
+package hubtest
+
+import (
+	"testing"
+
+	"github.com/johnabass/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakePublisher(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		fp FakePublisher
+		p  hub.Publisher = &fp
+	)
+
+	assert.Empty(fp.Published())
+
+	p.Publish(1)
+	p.Publish("two")
+	p.Publish(nil)
+
+	published := fp.Published()
+	assert.Equal([]interface{}{1, "two", nil}, published)
+
+	fp.Reset()
+	assert.Empty(fp.Published())
+	assert.Len(published, 3)
+}
+
+func TestFakeSubscriber(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received    []int
+		afterCancel int
+		l           = func(e int) { received = append(received, e) }
+
+		fs                = NewFakeSubscriber()
+		s  hub.Subscriber = fs
+	)
+
+	cancel, err := s.Subscribe(l, func() { afterCancel++ })
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	_, err = s.Subscribe(func(int, int) {})
+	assert.Equal(hub.ErrInvalidFunction, err)
+
+	require.Len(fs.Subscribed(), 1)
+	assert.True(fs.Publish(1))
+	assert.False(fs.Publish("unhandled"))
+	assert.Equal([]int{1}, received)
+
+	fs.Reset()
+	assert.Empty(fs.Subscribed())
+	assert.Equal(1, afterCancel)
+	assert.False(fs.Publish(2))
+	assert.Equal([]int{1}, received)
+}
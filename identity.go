@@ -0,0 +1,68 @@
+package hub
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// funcKey is the identity of a function listener.  functions aren't comparable, so the
+// pointer to the function's closure is used instead.  each closure instance, including each
+// bound method value, has its own pointer.
+//
+// reflect only exposes a function's code pointer, which closures over the same code share, so
+// the closure pointer is read from the interface with unsafe.  see listenerKey.
+type funcKey struct {
+	p unsafe.Pointer
+}
+
+// methodKey is the identity of a listener registered via SubscribeMethod
+type methodKey struct {
+	receiver interface{}
+	name     string
+}
+
+// listenerKey computes an identity for a listener, which is used to detect duplicate subscriptions.
+// A nil key indicates that the listener's identity cannot be determined, and such listeners are never
+// considered duplicates.
+func listenerKey(l interface{}) interface{} {
+	t := reflect.TypeOf(l)
+	switch {
+	case t == nil:
+		return nil
+
+	case t.Kind() == reflect.Func:
+		// a func value is pointer-shaped, so the gc runtime stores it directly in the data word of the
+		// interface, where it points to a closure whose first word is the function's code pointer.  that
+		// layout is checked against reflect before it is relied upon.  a function whose layout does not
+		// match has no identity, so it is never considered a duplicate.
+		p := (*[2]unsafe.Pointer)(unsafe.Pointer(&l))[1]
+		if p == nil || uintptr(*(*unsafe.Pointer)(p)) != reflect.ValueOf(l).Pointer() {
+			return nil
+		}
+
+		return funcKey{p: p}
+
+	case t.Comparable():
+		return l
+
+	default:
+		return nil
+	}
+}
+
+// sameKey tests if two listener keys are equal.  keys that are nil never match.  a comparable type
+// can still hold an incomparable value in an interface field, so a failed comparison is treated as
+// a mismatch rather than allowed to panic.
+func sameKey(a, b interface{}) (equal bool) {
+	if a == nil || b == nil {
+		return false
+	}
+
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+
+	return a == b
+}
@@ -0,0 +1,154 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type incomparableListener struct {
+	v interface{}
+}
+
+func (il incomparableListener) On(int) {}
+
+func TestListenerKey(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		c  = make(chan int)
+		f  = func(int) {}
+		l1 = new(mockListener)
+		l2 = new(mockListener)
+
+		closure = func(n int) func(int) {
+			return func(v int) { n += v }
+		}
+	)
+
+	assert.Nil(listenerKey(nil))
+	assert.Nil(listenerKey([]int{}))
+
+	assert.True(sameKey(listenerKey(c), listenerKey(c)))
+	assert.False(sameKey(listenerKey(c), listenerKey(make(chan int))))
+
+	assert.True(sameKey(listenerKey(f), listenerKey(f)))
+	assert.Nil(listenerKey((func(int))(nil)))
+
+	// distinct closures over the same code share a code pointer, but not an identity
+	c1, c2 := closure(1), closure(1)
+	assert.Equal(reflect.ValueOf(c1).Pointer(), reflect.ValueOf(c2).Pointer())
+	assert.NotNil(listenerKey(c1))
+	assert.True(sameKey(listenerKey(c1), listenerKey(c1)))
+	assert.False(sameKey(listenerKey(c1), listenerKey(c2)))
+
+	assert.True(sameKey(listenerKey(l1), listenerKey(l1)))
+	assert.False(sameKey(listenerKey(l1), listenerKey(l2)))
+	assert.NotNil(listenerKey(l1.OnEvent))
+	assert.False(sameKey(listenerKey(l1.OnEvent), listenerKey(l1.OnEvent)))
+
+	assert.False(sameKey(nil, nil))
+	assert.False(sameKey(
+		listenerKey(incomparableListener{v: []int{}}),
+		listenerKey(incomparableListener{v: []int{}}),
+	))
+}
+
+func testDedupEnabled(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c = make(chan int, 10)
+		h = New(WithDedup())
+	)
+
+	first, err := h.SubscribeWith(c)
+	require.NoError(err)
+
+	second, err := h.SubscribeWith(c)
+	require.NoError(err)
+	assert.True(first == second)
+
+	ml := new(MultiListener)
+	byName, err := h.SubscribeWith(ml.OnString)
+	require.NoError(err)
+
+	_, err = h.SubscribeMethod(ml, "OnString")
+	require.NoError(err)
+
+	_, err = h.SubscribeMethod(ml, "OnString")
+	require.NoError(err)
+
+	h.Publish(1)
+	h.Publish("one")
+	assert.Len(c, 1)
+	assert.Equal([]string{"one", "one"}, ml.strings)
+
+	first.Cancel()
+	byName.Cancel()
+	h.Publish(2)
+	assert.Len(c, 1)
+}
+
+func testDedupDisabled(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c = make(chan int, 10)
+		h = New()
+	)
+
+	first, err := h.SubscribeWith(c)
+	require.NoError(err)
+
+	second, err := h.SubscribeWith(c)
+	require.NoError(err)
+	assert.False(first == second)
+
+	h.Publish(1)
+	assert.Len(c, 2)
+}
+
+func testDedupNoDuplicates(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c = make(chan int, 10)
+		h = New(WithDedup(), WithNoDuplicates())
+	)
+
+	first, err := h.SubscribeWith(c)
+	require.NoError(err)
+
+	second, err := h.SubscribeWith(c)
+	assert.Equal(ErrDuplicateListener, err)
+	assert.Nil(second)
+
+	ml := new(MultiListener)
+	_, err = h.SubscribeMethod(ml, "OnString")
+	require.NoError(err)
+
+	_, err = h.SubscribeMethod(ml, "OnString")
+	assert.Equal(ErrDuplicateListener, err)
+
+	// the same listener may be subscribed again once cancelled
+	first.Cancel()
+	_, err = h.SubscribeWith(c)
+	assert.NoError(err)
+
+	h.Publish(1)
+	h.Publish("one")
+	assert.Len(c, 1)
+	assert.Equal([]string{"one"}, ml.strings)
+}
+
+func TestDedup(t *testing.T) {
+	t.Run("Enabled", testDedupEnabled)
+	t.Run("Disabled", testDedupDisabled)
+	t.Run("NoDuplicates", testDedupNoDuplicates)
+}
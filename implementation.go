@@ -0,0 +1,120 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrInvalidImplementation indicates that RegisterImplementation was passed something other than an
+// interface type and a concrete type that implements it.  The empty interface cannot be registered.
+var ErrInvalidImplementation = errors.New("The concrete type must be a non-interface type that implements the interface")
+
+// implementations is the registry maintained by RegisterImplementation.  like subscriptions, it
+// follows copy-on-write semantics so that publishes can consult it without locking.
+type implementations struct {
+	// interfaces is the set of interface types that may be used as event types
+	interfaces map[reflect.Type]bool
+
+	// byConcrete maps each registered concrete type onto the interfaces it was registered for
+	byConcrete map[reflect.Type][]reflect.Type
+}
+
+// add makes a copy of this registry with the given pair registered.  the returned flag is
+// false if the pair was already registered, in which case this registry is returned unchanged.
+func (i *implementations) add(iface, concrete reflect.Type) (*implementations, bool) {
+	for _, existing := range i.byConcrete[concrete] {
+		if existing == iface {
+			return i, false
+		}
+	}
+
+	clone := &implementations{
+		interfaces: make(map[reflect.Type]bool, len(i.interfaces)+1),
+		byConcrete: make(map[reflect.Type][]reflect.Type, len(i.byConcrete)+1),
+	}
+
+	for k, v := range i.interfaces {
+		clone.interfaces[k] = v
+	}
+
+	for k, v := range i.byConcrete {
+		clone.byConcrete[k] = v
+	}
+
+	existing := i.byConcrete[concrete]
+	updated := make([]reflect.Type, len(existing), len(existing)+1)
+	copy(updated, existing)
+
+	clone.interfaces[iface] = true
+	clone.byConcrete[concrete] = append(updated, iface)
+	return clone, true
+}
+
+// loadImplementations returns the current registry, which is never nil
+func (h *hub) loadImplementations() *implementations {
+	if i, ok := h.implementations.Load().(*implementations); ok {
+		return i
+	}
+
+	return new(implementations)
+}
+
+func (h *hub) RegisterImplementation(iface, concrete reflect.Type) error {
+	switch {
+	case iface == nil || iface.Kind() != reflect.Interface || iface == anyType:
+		return ErrInvalidImplementation
+
+	case concrete == nil || concrete.Kind() == reflect.Interface || !concrete.Implements(iface):
+		return ErrInvalidImplementation
+	}
+
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	if updated, ok := h.loadImplementations().add(iface, concrete); ok {
+		h.implementations.Store(updated)
+	}
+
+	return nil
+}
+
+// checkEventType verifies that a listener's event type may be subscribed to.  interfaces are only
+// allowed once registered via RegisterImplementation.
+func (h *hub) checkEventType(eventType reflect.Type) error {
+	if eventType.Kind() == reflect.Interface && !h.loadImplementations().interfaces[eventType] {
+		return ErrInvalidEventType
+	}
+
+	return nil
+}
+
+// route appends the buckets that receive a message with the given event type:  the bucket for the type itself,
+// the buckets for any registered interfaces the type implements, the bucket for the message's key when
+// keyed routing is used, and finally the catch-all bucket.  the total number of subscriptions across those
+// buckets is also returned.
+func (h *hub) route(buckets [][]*Subscription, eventType reflect.Type, m message) ([][]*Subscription, int) {
+	if h.presence.absent(eventType) {
+		return buckets, 0
+	}
+
+	count := 0
+	if eventType != nil {
+		typed := h.subscriptions.sinks(eventType)
+		buckets = append(buckets, typed)
+		count += len(typed)
+
+		for _, iface := range h.loadImplementations().byConcrete[eventType] {
+			implemented := h.subscriptions.sinks(iface)
+			buckets = append(buckets, implemented)
+			count += len(implemented)
+		}
+	}
+
+	if keyed := h.keyedSinks(m); len(keyed) > 0 {
+		buckets = append(buckets, keyed)
+		count += len(keyed)
+	}
+
+	all := h.subscriptions.sinks(anyType)
+	return append(buckets, all), count + len(all)
+}
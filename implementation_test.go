@@ -0,0 +1,77 @@
+package hub
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedEvent string
+
+func (ne namedEvent) String() string {
+	return string(ne)
+}
+
+type otherNamedEvent int
+
+func (one otherNamedEvent) String() string {
+	return fmt.Sprint(int(one))
+}
+
+func TestRegisterImplementation(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+		received     []string
+		concrete     []namedEvent
+		h            = New()
+	)
+
+	// interfaces must be registered before they can be subscribed to
+	_, err := h.Subscribe(func(fmt.Stringer) {})
+	assert.Equal(ErrInvalidEventType, err)
+
+	require.NoError(h.RegisterImplementation(stringerType, reflect.TypeOf(namedEvent(""))))
+	require.NoError(h.RegisterImplementation(stringerType, reflect.TypeOf(namedEvent(""))))
+
+	Must(h.Subscribe(func(s fmt.Stringer) { received = append(received, s.String()) }))
+	Must(h.Subscribe(func(ne namedEvent) { concrete = append(concrete, ne) }))
+
+	h.Publish(namedEvent("registered"))
+	assert.Equal([]string{"registered"}, received)
+	assert.Equal([]namedEvent{"registered"}, concrete)
+
+	// otherNamedEvent implements fmt.Stringer, but was never registered
+	assert.False(h.PublishOK(otherNamedEvent(1)))
+	assert.Equal([]string{"registered"}, received)
+
+	require.NoError(h.RegisterImplementation(stringerType, reflect.TypeOf(otherNamedEvent(0))))
+	assert.True(h.PublishOK(otherNamedEvent(2)))
+	assert.Equal([]string{"registered", "2"}, received)
+
+	// interface channels work as well
+	c := make(chan fmt.Stringer, 1)
+	Must(h.Subscribe(c))
+	h.Publish(namedEvent("channel"))
+	assert.Equal(namedEvent("channel"), <-c)
+
+	for _, invalid := range [][2]reflect.Type{
+		{nil, reflect.TypeOf(namedEvent(""))},
+		{stringerType, nil},
+		{reflect.TypeOf(namedEvent("")), reflect.TypeOf(namedEvent(""))},
+		{anyType, reflect.TypeOf(namedEvent(""))},
+		{stringerType, stringerType},
+		{stringerType, reflect.TypeOf(0)},
+	} {
+		assert.Equal(ErrInvalidImplementation, h.RegisterImplementation(invalid[0], invalid[1]))
+	}
+
+	// the empty interface is never a valid event type
+	_, err = h.Subscribe(func(interface{}) {})
+	assert.Equal(ErrInvalidEventType, err)
+}
@@ -0,0 +1,93 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrNoKeyFunc indicates an attempt to use SubscribeKey with a hub that was not created with WithKeyFunc
+	ErrNoKeyFunc = errors.New("The hub has no key function")
+
+	// ErrInvalidKey indicates that a routing key passed to SubscribeKey was nil or not comparable
+	ErrInvalidKey = errors.New("A routing key must be a non-nil, comparable value")
+)
+
+// WithKeyFunc enables keyed routing, which is an alternate routing strategy suited to tagged unions and
+// similar designs where the Go type of an event does not identify it.  Each published event is passed to f,
+// and the resulting key selects the listeners registered with SubscribeKey for that key.  Keyed routing is
+// in addition to the usual type-based routing, which is unaffected.
+//
+// f may return nil to indicate that an event has no key.  Any key it returns must be comparable.
+// f is invoked for every event published, including events that no keyed listener is interested in, so it
+// should be fast.  It is never invoked for a nil event.
+func WithKeyFunc(f func(interface{}) interface{}) Option {
+	return func(h *hub) {
+		h.keyFunc = f
+	}
+}
+
+// keyedRoute is the route for subscriptions made via SubscribeKey.  wrapping the key ensures that it
+// can never collide with an event type.
+type keyedRoute struct {
+	key interface{}
+}
+
+// sinkKeyed guards a keyed subscription's sink.  events with the same key may have different types, so
+// only events that the listener can actually accept are sent to it.
+type sinkKeyed struct {
+	eventType reflect.Type
+	sink      sink
+}
+
+func (sk *sinkKeyed) send(m message) {
+	if m.value.Type().AssignableTo(sk.eventType) {
+		sk.sink.send(m)
+	}
+}
+
+func (h *hub) SubscribeKey(key interface{}, l interface{}, afterCancel ...func()) (Cancel, error) {
+	if h.keyFunc == nil {
+		return nil, ErrNoKeyFunc
+	}
+
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		return nil, ErrInvalidKey
+	}
+
+	eventType, s, err := newSink(l)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	sub, err := h.register(
+		eventType,
+		&sinkKeyed{eventType: eventType, sink: s},
+		listenerKey(l),
+		func(sub *Subscription) { sub.route = keyedRoute{key: key} },
+		WithAfterCancel(afterCancel...),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.cancelFunc(), nil
+}
+
+// keyedSinks returns the keyed subscriptions for a message, if this hub uses keyed routing
+func (h *hub) keyedSinks(m message) []*Subscription {
+	if h.keyFunc == nil || !m.value.IsValid() {
+		return nil
+	}
+
+	if key := h.keyFunc(m.event()); key != nil {
+		return h.subscriptions.sinks(keyedRoute{key: key})
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type taggedEvent struct {
+	Tag   string
+	Value int
+}
+
+func testSubscribeKeyRouting(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		created []int
+		deleted []int
+		all     []taggedEvent
+		ints    []int
+
+		h = New(WithKeyFunc(func(e interface{}) interface{} {
+			switch v := e.(type) {
+			case taggedEvent:
+				return v.Tag
+			case int:
+				return "created"
+			default:
+				return nil
+			}
+		}))
+	)
+
+	cancel, err := h.SubscribeKey("created", func(e taggedEvent) { created = append(created, e.Value) })
+	require.NoError(err)
+	Must(h.SubscribeKey("deleted", func(e taggedEvent) { deleted = append(deleted, e.Value) }))
+	Must(h.SubscribeKey("created", func(e int) { ints = append(ints, e) }))
+	Must(h.Subscribe(func(e taggedEvent) { all = append(all, e) }))
+
+	h.Publish(taggedEvent{Tag: "created", Value: 1})
+	h.Publish(taggedEvent{Tag: "deleted", Value: 2})
+	h.Publish(taggedEvent{Tag: "unknown", Value: 3})
+
+	// the int listener shares a key, but only receives events of its own type
+	h.Publish(4)
+
+	assert.Equal([]int{1}, created)
+	assert.Equal([]int{2}, deleted)
+	assert.Equal([]int{4}, ints)
+	assert.Len(all, 3, "type-based routing is unaffected")
+
+	assert.Equal(4, h.Stats().TotalSubscriptions)
+	assert.Equal(1, h.Stats().EventTypeCount)
+
+	cancel()
+	h.Publish(taggedEvent{Tag: "created", Value: 5})
+	assert.Equal([]int{1}, created)
+}
+
+func testSubscribeKeyInvalid(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	_, err := h.SubscribeKey("key", func(int) {})
+	assert.Equal(ErrNoKeyFunc, err)
+
+	h = New(WithKeyFunc(func(interface{}) interface{} { return nil }))
+	_, err = h.SubscribeKey(nil, func(int) {})
+	assert.Equal(ErrInvalidKey, err)
+
+	_, err = h.SubscribeKey([]string{"not comparable"}, func(int) {})
+	assert.Equal(ErrInvalidKey, err)
+
+	_, err = h.SubscribeKey("key", func(int, int) {})
+	assert.Equal(ErrInvalidFunction, err)
+}
+
+func TestSubscribeKey(t *testing.T) {
+	t.Run("Routing", testSubscribeKeyRouting)
+	t.Run("Invalid", testSubscribeKeyInvalid)
+}
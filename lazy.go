@@ -0,0 +1,109 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// exhausted tests if this subscription has already received every event permitted by WithLimit
+func (s *Subscription) exhausted() bool {
+	return s.limit > 0 && atomic.LoadUint64(&s.occurrences) >= s.skip+s.limit
+}
+
+// mayReceive tests if an event of the given type could be delivered to at least one sink.  it errs on the side
+// of true:  routing that depends on the event itself, such as middleware, keyed routing, and forwarding within a
+// hierarchy, is assumed to deliver it, as is any subscription with an interceptor.  a subscription still skipping
+// events via WithSkip must see the event to count it, so it also counts as a receiver.
+func (h *hub) mayReceive(eventType reflect.Type) bool {
+	if len(h.middleware) > 0 || h.keyFunc != nil || h.loadParent() != nil || len(h.loadChildren()) > 0 {
+		return true
+	}
+
+	var fixed [3][]*Subscription
+	buckets, _ := h.route(fixed[:0], eventType, message{})
+	if to, ok := h.loadAliases()[eventType]; ok {
+		buckets = append(buckets, h.subscriptions.sinks(to))
+	}
+
+	for _, sinks := range buckets {
+		for _, s := range sinks {
+			if s.Enabled() && (s.interceptor != nil || !s.exhausted()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (h *hub) PublishLazy(eventType reflect.Type, build func() interface{}) {
+	if build == nil || !h.mayReceive(eventType) {
+		return
+	}
+
+	h.publish(eventType, build(), Meta{}, nil)
+}
+
+// sinkLazy is a sink whose listener is built by a factory when the first event arrives.  the factory runs
+// at most once, and any error from building is reported for that event and every later one.
+type sinkLazy struct {
+	eventType reflect.Type
+	factory   func() interface{}
+	onError   func(error)
+
+	once   sync.Once
+	target sink
+	err    error
+}
+
+// build invokes the factory and validates its listener exactly as Subscribe would.  a panicking factory
+// is treated like one that returned an invalid listener, since the once is done either way and there
+// would otherwise be no target for later events.
+func (sl *sinkLazy) build() {
+	defer func() {
+		if r := recover(); r != nil {
+			sl.err = panicError(r)
+			sl.onError(sl.err)
+		}
+	}()
+
+	eventType, target, err := newSink(sl.factory())
+	if err == nil && eventType != sl.eventType {
+		err = ErrInvalidEventType
+	}
+
+	if err != nil {
+		sl.err = err
+		sl.onError(err)
+		return
+	}
+
+	sl.target = target
+}
+
+func (sl *sinkLazy) send(m message) {
+	sl.once.Do(sl.build)
+	if sl.err != nil {
+		m.fail(Errored, sl.err)
+		return
+	}
+
+	sl.target.send(m)
+}
+
+func (h *hub) SubscribeLazy(eventType reflect.Type, factory func() interface{}) (Cancel, error) {
+	if eventType == nil {
+		return nil, ErrInvalidEventType
+	}
+
+	if factory == nil {
+		return nil, ErrInvalidListener
+	}
+
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	return h.registerCancel(eventType, &sinkLazy{eventType: eventType, factory: factory, onError: h.handleError}, nil)
+}
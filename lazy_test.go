@@ -0,0 +1,196 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPublishLazyBuild(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		eventType = reflect.TypeOf(TestEvent{})
+		builds    int
+		build     = func() interface{} {
+			builds++
+			return TestEvent{Value: builds}
+		}
+
+		h = New()
+
+		first  []TestEvent
+		second []TestEvent
+	)
+
+	// no subscriptions means nothing is built
+	h.PublishLazy(eventType, build)
+	assert.Zero(builds)
+	assert.Zero(h.PublishCounts()[eventType])
+
+	Must(h.Subscribe(func(e TestEvent) { first = append(first, e) }))
+	Must(h.Subscribe(func(e TestEvent) { second = append(second, e) }))
+
+	// the event is built once and shared
+	h.PublishLazy(eventType, build)
+	assert.Equal(1, builds)
+	assert.Equal([]TestEvent{{Value: 1}}, first)
+	assert.Equal([]TestEvent{{Value: 1}}, second)
+	assert.Equal(uint64(1), h.PublishCounts()[eventType])
+
+	h.PublishLazy(eventType, nil)
+	assert.Equal(uint64(1), h.PublishCounts()[eventType])
+}
+
+func testPublishLazyFiltered(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		eventType = reflect.TypeOf(TestEvent{})
+		builds    int
+		build     = func() interface{} {
+			builds++
+			return TestEvent{Value: builds}
+		}
+
+		h        = New()
+		received []TestEvent
+	)
+
+	disabled, err := h.SubscribeWith(func(e TestEvent) { received = append(received, e) })
+	require.NoError(err)
+	disabled.SetEnabled(false)
+
+	limited, err := h.SubscribeWith(func(e TestEvent) { received = append(received, e) }, WithLimit(1))
+	require.NoError(err)
+
+	h.PublishLazy(eventType, build)
+	assert.Equal(1, builds)
+	assert.False(limited.Active())
+
+	// only the disabled subscription remains
+	h.PublishLazy(eventType, build)
+	assert.Equal(1, builds)
+
+	// an interceptor must see the event to decide
+	_, err = h.SubscribeWith(func(TestEvent) {}, WithInterceptor(func(interface{}) bool { return false }))
+	require.NoError(err)
+	h.PublishLazy(eventType, build)
+	assert.Equal(2, builds)
+	assert.Equal([]TestEvent{{Value: 1}}, received)
+}
+
+func TestPublishLazy(t *testing.T) {
+	t.Run("Build", testPublishLazyBuild)
+	t.Run("Filtered", testPublishLazyFiltered)
+}
+
+func testSubscribeLazyBuild(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		builds   int
+		received []TestEvent
+		factory  = func() interface{} {
+			builds++
+			return func(e TestEvent) { received = append(received, e) }
+		}
+
+		h = New()
+	)
+
+	cancel, err := h.SubscribeLazy(reflect.TypeOf(TestEvent{}), factory)
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	// nothing is built until an event arrives
+	h.Publish("ignored")
+	assert.Zero(builds)
+
+	info := h.Describe()
+	require.Len(info, 1)
+	assert.Equal(LazySink, info[0].Kind)
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(TestEvent{Value: 2})
+	assert.Equal(1, builds)
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 2}}, received)
+
+	cancel()
+	h.Publish(TestEvent{Value: 3})
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 2}}, received)
+}
+
+func testSubscribeLazyInvalid(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		errs []error
+		h    = New(WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	)
+
+	_, err := h.SubscribeLazy(nil, func() interface{} { return nil })
+	assert.Equal(ErrInvalidEventType, err)
+
+	_, err = h.SubscribeLazy(reflect.TypeOf(TestEvent{}), nil)
+	assert.Equal(ErrInvalidListener, err)
+
+	// the factory's listener must be valid for the declared type
+	builds := 0
+	_, err = h.SubscribeLazy(reflect.TypeOf(TestEvent{}), func() interface{} {
+		builds++
+		return func(int) {}
+	})
+
+	require.NoError(err)
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(TestEvent{Value: 2})
+	assert.Equal(1, builds)
+	assert.Equal([]error{ErrInvalidEventType}, errs)
+
+	_, err = h.SubscribeLazy(reflect.TypeOf(0), func() interface{} { return "not a listener" })
+	require.NoError(err)
+	h.Publish(1)
+	assert.Equal([]error{ErrInvalidEventType, ErrInvalidListener}, errs)
+}
+
+func testSubscribeLazyPanic(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		errs []error
+		h    = New(WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	)
+
+	builds := 0
+	_, err := h.SubscribeLazy(reflect.TypeOf(0), func() interface{} {
+		builds++
+		panic("expected")
+	})
+
+	require.NoError(err)
+
+	// the panic is reported once, and later events fail with the same error rather than reaching a nil listener
+	assert.NotPanics(func() { h.Publish(1) })
+	assert.NotPanics(func() { h.Publish(2) })
+	assert.Equal(1, builds)
+	require.Len(errs, 1)
+	assert.Equal(&PanicError{Value: "expected"}, errs[0])
+
+	report := h.PublishReport(3)
+	require.Len(report.Results, 1)
+	assert.Equal(Errored, report.Results[0].Outcome)
+	assert.Equal(errs[0], report.Results[0].Err)
+}
+
+func TestSubscribeLazy(t *testing.T) {
+	t.Run("Build", testSubscribeLazyBuild)
+	t.Run("Invalid", testSubscribeLazyInvalid)
+	t.Run("Panic", testSubscribeLazyPanic)
+}
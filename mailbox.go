@@ -0,0 +1,162 @@
+package hub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMailboxCapacity is the capacity used by WithMailbox when the given capacity is less than 1
+	DefaultMailboxCapacity = 100
+
+	// DefaultDrainTimeout is the drain timeout used when WithDrainTimeout is not set
+	DefaultDrainTimeout = 5 * time.Second
+)
+
+// WithMailbox gives a subscription its own goroutine and a queue of the given capacity.  Publishing places the
+// event in the queue and returns, and the goroutine delivers queued events to the listener one at a time and in
+// order.  A publisher blocks while the queue is full.  A capacity less than 1 uses DefaultMailboxCapacity.
+//
+// Since delivery happens after the publish has returned, a mailbox listener's panics are recovered and logged,
+// and its outcome is not part of PublishReport or PublishGroup.  When the subscription is cancelled, events still
+// in the queue are discarded and counted in Stats unless WithDrainOnCancel is used.  Any afterCancel closures run
+// on the mailbox goroutine once it stops, so a channel closed by WithCloseOnCancel never receives a send after it
+// is closed.
+func WithMailbox(capacity int) SubscribeOption {
+	return func(s *Subscription) {
+		if capacity < 1 {
+			capacity = DefaultMailboxCapacity
+		}
+
+		s.mailbox = capacity
+	}
+}
+
+// WithDrainOnCancel causes a subscription made with WithMailbox to deliver the events remaining in its queue
+// when it is cancelled, rather than discarding them.  Cancel does not wait for the drain, which is bounded by
+// the hub's WithDrainTimeout.  Events still queued when the timeout elapses are discarded and counted in Stats.
+// CloseAndDrain waits for draining mailboxes along with any consumers.  This option has no effect without
+// WithMailbox.
+func WithDrainOnCancel() SubscribeOption {
+	return func(s *Subscription) {
+		s.drain = true
+	}
+}
+
+// sinkMailbox queues events for a single goroutine that delivers them to another sink.  as with sinkBatch,
+// cancellation never waits for that goroutine, since a listener that cancelled its own subscription would
+// otherwise wait on itself.  the goroutine runs the subscription's afterCancel closures once it stops.
+type sinkMailbox struct {
+	sub         *Subscription
+	target      sink
+	afterCancel []func()
+
+	events    chan message
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// newSinkMailbox wraps the sink of a subscription made with WithMailbox.  the subscription's afterCancel
+// closures are taken over by the mailbox, which runs them after it stops.
+func newSinkMailbox(sub *Subscription) *sinkMailbox {
+	mb := &sinkMailbox{
+		sub:         sub,
+		target:      sub.sink,
+		afterCancel: sub.afterCancel,
+		events:      make(chan message, sub.mailbox),
+		stop:        make(chan struct{}),
+	}
+
+	sub.sink = mb
+	sub.afterCancel = []func(){mb.close}
+	return mb
+}
+
+func (mb *sinkMailbox) send(m message) {
+	// the event is delivered after the publish returns, so it cannot take part in a report or group
+	m = m.detach()
+	m.result = nil
+
+	select {
+	case mb.events <- m:
+	case <-mb.stop:
+		// the subscription was cancelled while this event was in flight
+	}
+}
+
+// start launches the goroutine, once the subscription has been added to its hub
+func (mb *sinkMailbox) start() {
+	mb.sub.hub.mailboxes.Add(1)
+	go mb.run()
+}
+
+// run is the goroutine that delivers queued events
+func (mb *sinkMailbox) run() {
+	h := mb.sub.hub
+	defer h.mailboxes.Done()
+	defer func() {
+		for _, f := range mb.afterCancel {
+			f()
+		}
+	}()
+
+	for {
+		select {
+		case <-mb.stop:
+			// cancellation takes priority over any queued events
+		default:
+			select {
+			case m := <-mb.events:
+				mb.deliver(m)
+				continue
+
+			case <-mb.stop:
+			}
+		}
+
+		if mb.sub.drain {
+			mb.drain(h.drainTimeout)
+		}
+
+		atomic.AddUint64(&h.counters.dropped, uint64(len(mb.events)))
+		return
+	}
+}
+
+// drain delivers queued events until the queue is empty or the timeout elapses
+func (mb *sinkMailbox) drain(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		select {
+		case m := <-mb.events:
+			mb.deliver(m)
+
+		default:
+			return
+		}
+	}
+}
+
+// deliver passes a single event to the target sink.  there is no caller to receive a panic, so any panic
+// is recovered and logged.
+func (mb *sinkMailbox) deliver(m message) {
+	defer func() {
+		if r := recover(); r != nil {
+			mb.sub.hub.logf("mailbox listener for %s panicked: %v", mb.sub.eventType, r)
+		}
+	}()
+
+	mb.target.send(m)
+}
+
+// close stops the goroutine.  this method does not wait.
+func (mb *sinkMailbox) close() {
+	mb.closeOnce.Do(func() {
+		close(mb.stop)
+	})
+}
@@ -0,0 +1,214 @@
+package hub
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingListener returns a listener that records events, blocking on the first one until release is closed
+func blockingListener(received *[]int, started chan<- struct{}, release <-chan struct{}) func(int) {
+	return func(e int) {
+		if len(*received) == 0 {
+			close(started)
+			<-release
+		}
+
+		*received = append(*received, e)
+	}
+}
+
+func testMailboxSerial(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []int
+		started  = make(chan struct{})
+		release  = make(chan struct{})
+		stopped  = make(chan struct{})
+		h        = New()
+	)
+
+	sub, err := h.SubscribeWith(
+		blockingListener(&received, started, release),
+		WithMailbox(10),
+		WithDrainOnCancel(),
+		WithAfterCancel(func() { close(stopped) }),
+	)
+
+	require.NoError(err)
+	assert.Equal([]SubscriptionInfo{{
+		EventType:     "int",
+		EventTypeName: "int",
+		Kind:          FuncSink,
+		Listener:      h.Describe()[0].Listener,
+	}}, h.Describe())
+
+	// publishing returns while the listener is still busy with the first event
+	for i := 1; i <= 3; i++ {
+		assert.True(h.PublishOK(i))
+	}
+
+	<-started
+	close(release)
+
+	// a drained, cancelled mailbox delivers everything that was queued, in order
+	assert.True(h.PublishOK(4))
+	h.Close()
+	assert.False(sub.Cancel())
+
+	<-stopped
+	assert.Equal([]int{1, 2, 3, 4}, received)
+}
+
+func testMailboxDiscard(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []int
+		started  = make(chan struct{})
+		release  = make(chan struct{})
+		stopped  = make(chan struct{})
+		h        = New()
+	)
+
+	sub, err := h.SubscribeWith(
+		blockingListener(&received, started, release),
+		WithMailbox(10),
+		WithAfterCancel(func() { close(stopped) }),
+	)
+
+	require.NoError(err)
+	h.Publish(1)
+	<-started
+	h.Publish(2)
+	h.Publish(3)
+
+	// the afterCancel closures run once the mailbox stops, which is after the listener returns
+	assert.True(sub.Cancel())
+	select {
+	case <-stopped:
+		assert.Fail("afterCancel ran while the listener was still busy")
+	default:
+	}
+
+	close(release)
+	<-stopped
+	assert.Equal([]int{1}, received)
+	assert.Equal(uint64(2), h.Stats().DroppedCount)
+}
+
+func testMailboxDrain(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []int
+		started  = make(chan struct{})
+		release  = make(chan struct{})
+		h        = New()
+	)
+
+	_, err := h.SubscribeWith(
+		blockingListener(&received, started, release),
+		WithMailbox(10),
+		WithDrainOnCancel(),
+	)
+
+	require.NoError(err)
+	h.Publish(1)
+	<-started
+	h.Publish(2)
+	h.Publish(3)
+
+	// CloseAndDrain waits for the mailbox to deliver what it had already accepted
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	assert.NoError(h.CloseAndDrain(5 * time.Second))
+	assert.Equal([]int{1, 2, 3}, received)
+	assert.Zero(h.Stats().DroppedCount)
+}
+
+func testMailboxDrainTimeout(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []int
+		stopped  = make(chan struct{})
+		h        = New(WithDrainTimeout(time.Millisecond))
+	)
+
+	_, err := h.SubscribeWith(
+		func(e int) {
+			time.Sleep(5 * time.Millisecond)
+			received = append(received, e)
+		},
+		WithMailbox(10),
+		WithDrainOnCancel(),
+		WithAfterCancel(func() { close(stopped) }),
+	)
+
+	require.NoError(err)
+	for i := 0; i < 5; i++ {
+		h.Publish(i)
+	}
+
+	// the drain gives up once the timeout elapses, so Close never waits on a slow listener
+	h.Close()
+	<-stopped
+	assert.NotEmpty(received)
+	assert.True(len(received) < 5)
+	assert.Equal(uint64(5-len(received)), h.Stats().DroppedCount)
+}
+
+func testMailboxPanic(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output   bytes.Buffer
+		received []int
+		stopped  = make(chan struct{})
+		h        = New(WithLogger(log.New(&output, "", 0)))
+	)
+
+	_, err := h.SubscribeWith(
+		func(e int) {
+			if e == 1 {
+				panic("expected")
+			}
+
+			received = append(received, e)
+		},
+		WithMailbox(0),
+		WithDrainOnCancel(),
+		WithAfterCancel(func() { close(stopped) }),
+	)
+
+	require.NoError(err)
+	assert.NotPanics(func() { h.Publish(1) })
+	h.Publish(2)
+	h.Close()
+
+	<-stopped
+	assert.Equal([]int{2}, received)
+	assert.Equal("mailbox listener for int panicked: expected\n", output.String())
+}
+
+func TestMailbox(t *testing.T) {
+	t.Run("Serial", testMailboxSerial)
+	t.Run("Discard", testMailboxDiscard)
+	t.Run("Drain", testMailboxDrain)
+	t.Run("DrainTimeout", testMailboxDrainTimeout)
+	t.Run("Panic", testMailboxPanic)
+}
@@ -0,0 +1,79 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+var metaType = reflect.TypeOf(Meta{})
+
+// Meta is metadata that accompanies an event without being part of the event's own type.  Listeners
+// receive metadata by declaring a second input parameter of type Meta, e.g. func(MyEvent, Meta).
+// Events published with plain Publish carry the zero Meta.
+type Meta struct {
+	// Time is when the event was published.  PublishMeta sets this to the current time if it is zero.
+	Time time.Time
+
+	// CorrelationID is an optional, caller-supplied identifier relating this event to others
+	CorrelationID string
+}
+
+// message is the internal representation of a single published event, passed to each sink
+type message struct {
+	value reflect.Value
+	meta  Meta
+
+	// transformed holds the source types of any transforms that produced this message
+	transformed []reflect.Type
+
+	// origin is the hub that forwarded this message, if it arrived from elsewhere in a hierarchy
+	// created by NewChild
+	origin *hub
+
+	// ctx is the context that accompanied the event, if any
+	ctx context.Context
+
+	// report collects the outcome of each delivery when publishing with PublishReport, and result is the
+	// outcome for the subscription currently receiving this message.  both are nil otherwise.
+	report *Report
+	result *SinkResult
+
+	// sub is the subscription currently receiving this message.  it is set for each sink as
+	// the message is delivered.
+	sub *Subscription
+
+	// remaining is the number of events that follow this one in a PublishBatch
+	remaining int
+
+	// except is the subscription that must not receive this message, if published with PublishExcept
+	except *Subscription
+
+	// sticky causes propagate to retain this message, if published with PublishSticky
+	sticky bool
+
+	// group collects the outcome of concurrent deliveries when publishing with PublishGroup.  it is nil otherwise.
+	group *groupState
+
+	// acks collects the acknowledgements that PublishAck must wait for.  it is nil otherwise.
+	acks *[]pendingAck
+}
+
+// event returns the event carried by this message.  a nil event produces an invalid value,
+// which cannot be converted back into an interface, so that case is handled here.
+func (m message) event() interface{} {
+	if m.value.IsValid() {
+		return m.value.Interface()
+	}
+
+	return nil
+}
+
+// context returns the context that accompanied this message, which is never nil
+func (m message) context() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+
+	return context.Background()
+}
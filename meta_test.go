@@ -0,0 +1,61 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MetaListener struct {
+	metas []Meta
+}
+
+func (ml *MetaListener) On(e TestEvent, meta Meta) {
+	ml.metas = append(ml.metas, meta)
+}
+
+func TestPublishMeta(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		funcMetas []Meta
+		events    []TestEvent
+		ml        = new(MetaListener)
+		h         = New()
+	)
+
+	_, err := h.Subscribe(func(e TestEvent, meta Meta) { funcMetas = append(funcMetas, meta) })
+	require.NoError(err)
+
+	_, err = h.Subscribe(ml)
+	require.NoError(err)
+
+	_, err = h.Subscribe(func(e TestEvent) { events = append(events, e) })
+	require.NoError(err)
+
+	published := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.PublishMeta(TestEvent{Value: 1}, Meta{Time: published, CorrelationID: "abc"})
+	h.Publish(TestEvent{Value: 2})
+
+	before := time.Now()
+	h.PublishMeta(TestEvent{Value: 3}, Meta{})
+
+	require.Len(funcMetas, 3)
+	assert.Equal(Meta{Time: published, CorrelationID: "abc"}, funcMetas[0])
+	assert.Equal(Meta{}, funcMetas[1])
+	assert.False(funcMetas[2].Time.Before(before))
+	assert.Equal(funcMetas, ml.metas)
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 2}, {Value: 3}}, events)
+
+	// only Meta is allowed as a second parameter
+	cancel, err := h.Subscribe(func(TestEvent, int) {})
+	assert.Equal(ErrInvalidFunction, err)
+	assert.Nil(cancel)
+
+	cancel, err = h.Subscribe(func(Meta, TestEvent) {})
+	assert.Equal(ErrInvalidFunction, err)
+	assert.Nil(cancel)
+}
@@ -0,0 +1,87 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+)
+
+// Middleware wraps the delivery of each event published to a hub.  A middleware receives the context that
+// accompanies the event along with the event itself, and calls next to continue delivery.  The context passed
+// to next, which may be derived from ctx, is the one that later middleware, observers, and context-aware
+// listeners receive.  For example, a tracing middleware can start a span, store it in the context, and finish
+// the span once next returns.
+//
+// A middleware that returns without calling next stops delivery of the event, which is then treated as
+// if no listener matched it.  next must be called at most once, and only before the middleware returns.
+type Middleware func(ctx context.Context, e interface{}, next func(context.Context))
+
+// WithMiddleware appends middleware to a hub.  Middleware runs in the order supplied, so the first middleware
+// is the outermost.  It applies to every publish method, and runs on the worker goroutine for PublishAsync.
+// Events forwarded by a parent or child hub created with NewChild pass only through the middleware of the hub
+// they were published to.
+func WithMiddleware(m ...Middleware) Option {
+	return func(h *hub) {
+		h.middleware = append(h.middleware, m...)
+	}
+}
+
+// intercept passes a message through this hub's middleware, starting at index i, and then propagates it.
+// it returns true if the message matched at least one sink.
+//
+// the call to each middleware lives in wrap, so that a hub without middleware never allocates the closure
+// passed as next.
+func (h *hub) intercept(i int, eventType reflect.Type, m message) bool {
+	switch {
+	case i < len(h.middleware):
+		return h.wrap(i, eventType, m)
+
+	case h.tracer != nil:
+		defer h.tracePublish(eventType, &m).End()
+		return h.propagate(eventType, m)
+
+	default:
+		return h.propagate(eventType, m)
+	}
+}
+
+// wrap invokes the middleware at index i, whose next continues with intercept
+func (h *hub) wrap(i int, eventType reflect.Type, m message) (handled bool) {
+	h.middleware[i](m.context(), m.event(), func(ctx context.Context) {
+		m.ctx = ctx
+		handled = h.intercept(i+1, eventType, m)
+	})
+
+	return
+}
+
+func (h *hub) PublishContext(ctx context.Context, e interface{}) {
+	eventType := reflect.TypeOf(e)
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: reflect.ValueOf(e), ctx: ctx}, nil)
+}
+
+func (h *hub) SubscribeContextHandler(l interface{}, afterCancel ...func()) (Cancel, error) {
+	eventType, s, err := newSink(l)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig signature
+	switch typed := s.(type) {
+	case *sinkFunc:
+		sig = typed.sig
+
+	case *sinkMethod:
+		sig = typed.sig
+	}
+
+	if !sig.has(paramContext) {
+		return nil, ErrInvalidFunction
+	}
+
+	if err := h.checkEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	return h.registerCancel(eventType, s, listenerKey(l), afterCancel...)
+}
@@ -0,0 +1,202 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type middlewareKey struct{}
+
+func testMiddlewareContext(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		order    []string
+		received []string
+		slow     []interface{}
+
+		h = New(
+			WithMiddleware(
+				func(ctx context.Context, e interface{}, next func(context.Context)) {
+					order = append(order, "outer")
+					next(context.WithValue(ctx, middlewareKey{}, "span"))
+					order = append(order, "outer done")
+				},
+				func(ctx context.Context, e interface{}, next func(context.Context)) {
+					order = append(order, "inner")
+					next(ctx)
+				},
+			),
+			WithSlowThreshold(time.Nanosecond),
+			WithObserver(Observer{
+				OnSlowListenerContext: func(ctx context.Context, eventType reflect.Type, d time.Duration) {
+					slow = append(slow, ctx.Value(middlewareKey{}))
+				},
+			}),
+		)
+	)
+
+	Must(h.Subscribe(func(e string, ctx context.Context) {
+		received = append(received, e+":"+ctx.Value(middlewareKey{}).(string))
+		time.Sleep(time.Millisecond)
+	}))
+
+	h.Publish("event")
+	assert.Equal([]string{"outer", "inner", "outer done"}, order)
+	assert.Equal([]string{"event:span"}, received)
+	assert.Equal([]interface{}{"span"}, slow)
+}
+
+func testMiddlewarePublishContext(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		seen     []interface{}
+		received []interface{}
+
+		h = New(WithMiddleware(func(ctx context.Context, e interface{}, next func(context.Context)) {
+			seen = append(seen, ctx.Value(middlewareKey{}))
+			next(ctx)
+		}))
+	)
+
+	Must(h.Subscribe(func(e int, ctx context.Context) {
+		received = append(received, ctx.Value(middlewareKey{}))
+	}))
+
+	h.PublishContext(context.WithValue(context.Background(), middlewareKey{}, "request"), 1)
+	h.Publish(2)
+	assert.Equal([]interface{}{"request", nil}, seen)
+	assert.Equal([]interface{}{"request", nil}, received)
+}
+
+func testMiddlewareStop(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received  []int
+		unhandled []interface{}
+
+		h = New(
+			WithMiddleware(func(ctx context.Context, e interface{}, next func(context.Context)) {
+				if e.(int) > 0 {
+					next(ctx)
+				}
+			}),
+			WithUnhandled(func(e interface{}) { unhandled = append(unhandled, e) }),
+		)
+	)
+
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+
+	h.Publish(1)
+	h.Publish(-1)
+	assert.Equal([]int{1}, received)
+	assert.Equal([]interface{}{-1}, unhandled)
+}
+
+func testMiddlewareAsync(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		events = make(chan interface{}, 1)
+
+		h = New(
+			WithWorkers(1),
+			WithMiddleware(func(ctx context.Context, e interface{}, next func(context.Context)) {
+				next(context.WithValue(ctx, middlewareKey{}, "async"))
+			}),
+		)
+	)
+
+	defer h.Close()
+	Must(h.Subscribe(func(e int, ctx context.Context) { events <- ctx.Value(middlewareKey{}) }))
+
+	require.True(h.PublishAsync(1))
+	select {
+	case v := <-events:
+		assert.Equal("async", v)
+	case <-time.After(time.Second):
+		assert.Fail("The event was not delivered")
+	}
+}
+
+type contextListener struct {
+	values []interface{}
+}
+
+func (cl *contextListener) OnEvent(ctx context.Context, e TestEvent) {
+	cl.values = append(cl.values, ctx.Value(middlewareKey{}))
+}
+
+func TestSubscribeContextHandler(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h   = New()
+		ctx = context.WithValue(context.Background(), middlewareKey{}, "request")
+
+		first  []interface{}
+		second []interface{}
+		cl     = new(contextListener)
+	)
+
+	cancel, err := h.SubscribeContextHandler(func(ctx context.Context, e int) {
+		first = append(first, ctx.Value(middlewareKey{}))
+	})
+
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	Must(h.SubscribeContextHandler(func(e int, ctx context.Context) {
+		second = append(second, ctx.Value(middlewareKey{}))
+	}))
+
+	Must(h.SubscribeContextHandler(cl))
+
+	h.PublishContext(ctx, 1)
+	h.Publish(2)
+	h.PublishContext(ctx, TestEvent{})
+	assert.Equal([]interface{}{"request", nil}, first)
+	assert.Equal([]interface{}{"request", nil}, second)
+	assert.Equal([]interface{}{"request"}, cl.values)
+
+	// listeners without a context are rejected
+	for _, invalid := range []interface{}{func(int) {}, make(chan int), func(int, Meta) {}} {
+		cancel, err = h.SubscribeContextHandler(invalid)
+		assert.Equal(ErrInvalidFunction, err)
+		assert.Nil(cancel)
+	}
+
+	cancel, err = h.SubscribeContextHandler(nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
+
+func testMiddlewareAllocations(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	// without middleware, publishing must not allocate the closure used to chain middleware
+	assert.Zero(testing.AllocsPerRun(100, func() { h.Publish(1) }))
+
+	Must(h.Subscribe(func(int) {}))
+	assert.Zero(testing.AllocsPerRun(100, func() { h.Publish(1) }))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Context", testMiddlewareContext)
+	t.Run("PublishContext", testMiddlewarePublishContext)
+	t.Run("Stop", testMiddlewareStop)
+	t.Run("Async", testMiddlewareAsync)
+	t.Run("Allocations", testMiddlewareAllocations)
+}
@@ -1,6 +1,9 @@
 package hub
 
 import (
+	"io"
+	"sync"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -14,3 +17,15 @@ type mockListener struct {
 func (m *mockListener) OnEvent(e TestEvent) {
 	m.m.Called(e)
 }
+
+// lockedWriter guards an io.Writer so that output written from other goroutines can be safely inspected
+type lockedWriter struct {
+	w    io.Writer
+	lock *sync.Mutex
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	return lw.w.Write(p)
+}
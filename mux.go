@@ -0,0 +1,56 @@
+package hub
+
+// Mux collects listeners for several event types so that they can be subscribed and cancelled together.
+// This is convenient for a component that handles a handful of distinct events:
+//
+//	m := hub.NewMux()
+//	m.Handle(func(e ServerStarted) { ... })
+//	m.Handle(func(e ServerStopped) { ... })
+//
+//	cancel, err := m.Subscribe(h)
+//
+// A Mux is not safe for concurrent use while listeners are being added.  Once built, it may be subscribed
+// any number of times, to any number of Subscribers.
+type Mux struct {
+	listeners []interface{}
+}
+
+// NewMux creates an empty Mux
+func NewMux() *Mux {
+	return new(Mux)
+}
+
+// Handle adds a listener to this Mux.  The listener may be anything accepted by Subscriber.Subscribe, and is
+// validated immediately, so that an invalid listener is reported here rather than by Subscribe.  Interface event
+// types are the exception, since whether they are allowed depends on the hub.  See RegisterImplementation.
+func (m *Mux) Handle(l interface{}) error {
+	if _, _, err := newSink(l); err != nil {
+		return err
+	}
+
+	m.listeners = append(m.listeners, l)
+	return nil
+}
+
+// Subscribe registers every listener added to this Mux with s, returning a single Cancel that removes them all.
+// If any listener cannot be subscribed, the listeners already subscribed are cancelled and the error is returned.
+func (m *Mux) Subscribe(s Subscriber) (Cancel, error) {
+	cancels := make([]Cancel, 0, len(m.listeners))
+	cancelAll := func() {
+		for _, c := range cancels {
+			c()
+		}
+	}
+
+	for _, l := range m.listeners {
+		c, err := s.Subscribe(l)
+		if err != nil {
+			cancelAll()
+			return nil, err
+		}
+
+		cancels = append(cancels, c)
+	}
+
+	return cancelAll, nil
+}
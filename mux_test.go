@@ -0,0 +1,54 @@
+package hub
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMux(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		ints    []int
+		strings []string
+		events  = make(chan TestEvent, 1)
+
+		m = NewMux()
+		h = New()
+	)
+
+	require.NoError(m.Handle(func(e int) { ints = append(ints, e) }))
+	require.NoError(m.Handle(func(e string) { strings = append(strings, e) }))
+	require.NoError(m.Handle(events))
+
+	// invalid listeners are reported immediately and not added
+	assert.Equal(ErrInvalidListener, m.Handle(nil))
+	assert.Equal(ErrInvalidFunction, m.Handle(func(int, int) {}))
+
+	cancel, err := m.Subscribe(h)
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	h.Publish(1)
+	h.Publish("one")
+	h.Publish(TestEvent{Value: 1})
+	assert.Equal([]int{1}, ints)
+	assert.Equal([]string{"one"}, strings)
+	assert.Equal(TestEvent{Value: 1}, <-events)
+
+	cancel()
+	assert.False(h.PublishOK(2))
+	assert.False(h.PublishOK("two"))
+	assert.False(h.PublishOK(TestEvent{Value: 2}))
+
+	// a failure cancels the listeners already subscribed
+	require.NoError(m.Handle(func(io.Reader) {}))
+	cancel, err = m.Subscribe(h)
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+	assert.Zero(h.Stats().TotalSubscriptions)
+}
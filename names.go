@@ -0,0 +1,62 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrInvalidTypeName indicates that RegisterEventTypeNamed was passed an empty name or a nil type, or that
+	// the name is already registered for a different type
+	ErrInvalidTypeName = errors.New("An event type name must be non-empty and refer to a single type")
+
+	// ErrUnknownTypeName indicates that no event type has been registered under a name
+	ErrUnknownTypeName = errors.New("No event type is registered with that name")
+)
+
+func (h *hub) RegisterEventTypeNamed(name string, eventType reflect.Type) error {
+	if len(name) == 0 || eventType == nil {
+		return ErrInvalidTypeName
+	}
+
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	h.registerType(eventType)
+	if existing, ok := h.typeNames[name]; ok {
+		if existing != eventType {
+			return ErrInvalidTypeName
+		}
+
+		return nil
+	}
+
+	if h.typeNames == nil {
+		h.typeNames = make(map[string]reflect.Type)
+	}
+
+	h.typeNames[name] = eventType
+	return nil
+}
+
+// eventTypeNamed looks up a type registered via RegisterEventTypeNamed
+func (h *hub) eventTypeNamed(name string) (reflect.Type, error) {
+	h.subscribeLock.Lock()
+	eventType, ok := h.typeNames[name]
+	h.subscribeLock.Unlock()
+
+	if !ok {
+		return nil, ErrUnknownTypeName
+	}
+
+	return eventType, nil
+}
+
+func (h *hub) SubscribeByTypeName(name string, l func(interface{}), afterCancel ...func()) (Cancel, error) {
+	eventType, err := h.eventTypeNamed(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.SubscribeFor(eventType, l, afterCancel...)
+}
@@ -0,0 +1,63 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEventTypeNamedRegister(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h = New()
+	)
+
+	assert.NoError(h.RegisterEventTypeNamed("test", reflect.TypeOf(TestEvent{})))
+	assert.NoError(h.RegisterEventTypeNamed("test", reflect.TypeOf(TestEvent{})))
+	assert.Equal(ErrInvalidTypeName, h.RegisterEventTypeNamed("test", reflect.TypeOf(0)))
+	assert.Equal(ErrInvalidTypeName, h.RegisterEventTypeNamed("", reflect.TypeOf(0)))
+	assert.Equal(ErrInvalidTypeName, h.RegisterEventTypeNamed("nil", nil))
+}
+
+func testEventTypeNamedSubscribe(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+
+		byName []interface{}
+		byType []TestEvent
+	)
+
+	cancel, err := h.SubscribeByTypeName("test", func(e interface{}) {})
+	assert.Equal(ErrUnknownTypeName, err)
+	assert.Nil(cancel)
+
+	require.NoError(h.RegisterEventTypeNamed("test", reflect.TypeOf(TestEvent{})))
+	cancel, err = h.SubscribeByTypeName("test", func(e interface{}) { byName = append(byName, e) })
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	Must(h.Subscribe(func(e TestEvent) { byType = append(byType, e) }))
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(2)
+	cancel()
+	h.Publish(TestEvent{Value: 3})
+
+	assert.Equal([]interface{}{TestEvent{Value: 1}}, byName)
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 3}}, byType)
+
+	cancel, err = h.SubscribeByTypeName("test", nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
+
+func TestEventTypeNamed(t *testing.T) {
+	t.Run("Register", testEventTypeNamedRegister)
+	t.Run("Subscribe", testEventTypeNamedSubscribe)
+}
@@ -0,0 +1,92 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Observer receives instrumentation callbacks from a hub.  Each callback is optional, and a nil
+// callback costs nothing during delivery.  Callbacks are invoked synchronously, so they must be fast
+// and must not publish to the hub that invokes them.
+type Observer struct {
+	// OnSlowListener is invoked when delivering an event to a single sink takes longer than the
+	// threshold configured via WithSlowThreshold.  Timing uses the monotonic clock.
+	OnSlowListener func(eventType reflect.Type, d time.Duration)
+
+	// OnSlowListenerContext is the same as OnSlowListener, but also receives the context that accompanied
+	// the event.  This allows a slow delivery to be associated with, e.g., a span started by middleware.
+	// Both callbacks are invoked if both are set.
+	OnSlowListenerContext func(ctx context.Context, eventType reflect.Type, d time.Duration)
+
+	// OnQueueDepth is invoked with the number of pending asynchronous deliveries each time that number
+	// changes.  A delivery is pending from the time PublishAsync enqueues it until a worker finishes it.
+	OnQueueDepth func(depth int)
+
+	// OnQueueLatency is invoked each time a worker takes an asynchronous delivery from the queue, with the time
+	// the delivery spent waiting since PublishAsync or PublishUrgent enqueued it.  Consistently high latency
+	// suggests that more workers are needed.  Synchronous publishes never invoke this callback.
+	OnQueueLatency func(d time.Duration)
+
+	// OnSubscribe is invoked each time a subscription is added, and OnCancel each time one is cancelled by
+	// any means, including Close.  A subscription is reported to OnCancel only once.  Comparing the two over
+	// time reveals leaks, where subscriptions are added faster than they are cancelled.  See also Stats.
+	OnSubscribe func(eventType reflect.Type)
+	OnCancel    func(eventType reflect.Type)
+
+	// OnDelivery is invoked after each delivery to a single sink, with the subscription's event type and the kind of
+	// sink as given by SinkKind.String.  err is nil if the listener returned normally, and otherwise holds its panic
+	// converted to an error.  Under the Propagate policy, the panic is reported before it escapes Publish.  Events
+	// vetoed by an interceptor are not delivered, and so are not reported.
+	OnDelivery func(eventType reflect.Type, sinkKind string, err error)
+}
+
+// timed tests if deliveries should be timed for slow listener detection
+func (h *hub) timed() bool {
+	return h.slowThreshold > 0 && (h.observer.OnSlowListener != nil || h.observer.OnSlowListenerContext != nil)
+}
+
+// sendTo delivers a message to a single subscription, timing the delivery if slow listener
+// detection is enabled and tracing it if this hub has a tracer.  If the subscription's interceptor vetoes the message, nothing is
+// delivered and this method returns false.
+func (h *hub) sendTo(s *Subscription, m message) bool {
+	m.sub = s
+	if s.interceptor != nil && !s.interceptor(m.event()) {
+		return false
+	}
+
+	if h.tracer != nil {
+		defer h.traceDeliver(s, &m).End()
+	}
+
+	if !h.timed() {
+		s.sink.send(m)
+		return true
+	}
+
+	start := time.Now()
+	s.sink.send(m)
+	if d := time.Since(start); d > h.slowThreshold {
+		h.slowListener(m, d)
+	}
+
+	return true
+}
+
+// delivered reports the outcome of a delivery to the observer
+func (h *hub) delivered(s *Subscription, err error) {
+	if h.observer.OnDelivery != nil {
+		h.observer.OnDelivery(s.eventType, kindOf(s.sink).String(), err)
+	}
+}
+
+// slowListener reports a slow delivery to the observer
+func (h *hub) slowListener(m message, d time.Duration) {
+	if h.observer.OnSlowListener != nil {
+		h.observer.OnSlowListener(m.sub.eventType, d)
+	}
+
+	if h.observer.OnSlowListenerContext != nil {
+		h.observer.OnSlowListenerContext(m.context(), m.sub.eventType, d)
+	}
+}
@@ -0,0 +1,102 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowListener(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		slowTypes []reflect.Type
+		durations []time.Duration
+
+		h = New(
+			WithSlowThreshold(5*time.Millisecond),
+			WithObserver(Observer{
+				OnSlowListener: func(eventType reflect.Type, d time.Duration) {
+					slowTypes = append(slowTypes, eventType)
+					durations = append(durations, d)
+				},
+			}),
+		)
+	)
+
+	Must(h.Subscribe(func(int) {}))
+	Must(h.Subscribe(func(string) { time.Sleep(20 * time.Millisecond) }))
+
+	h.Publish(1)
+	h.Publish("slow")
+
+	assert.Equal([]reflect.Type{reflect.TypeOf("")}, slowTypes)
+	if assert.Len(durations, 1) {
+		assert.True(durations[0] > 5*time.Millisecond)
+	}
+}
+
+func TestSlowListenerDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	// neither an observer without a threshold nor a threshold without an observer times deliveries
+	assert.False(New(WithObserver(Observer{OnSlowListener: func(reflect.Type, time.Duration) {}})).(*hub).timed())
+	assert.False(New(WithSlowThreshold(time.Second)).(*hub).timed())
+}
+
+func TestOnDelivery(t *testing.T) {
+	type delivery struct {
+		eventType reflect.Type
+		sinkKind  string
+		err       error
+	}
+
+	testData := []struct {
+		panicPolicy PanicPolicy
+	}{
+		{Propagate},
+		{Recover},
+	}
+
+	for _, record := range testData {
+		t.Run(record.panicPolicy.String(), func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+
+				deliveries []delivery
+				h          = New(
+					WithPanicPolicy(record.panicPolicy),
+					WithObserver(Observer{
+						OnDelivery: func(eventType reflect.Type, sinkKind string, err error) {
+							deliveries = append(deliveries, delivery{eventType, sinkKind, err})
+						},
+					}),
+				)
+
+				expectedErr = errors.New("expected")
+			)
+
+			Must(h.Subscribe(func(int) {}))
+			Must(h.Subscribe(make(chan int, 1)))
+			Must(h.Subscribe(func(int) { panic(expectedErr) }))
+
+			if record.panicPolicy == Propagate {
+				assert.PanicsWithValue(expectedErr, func() { h.Publish(1) })
+			} else {
+				h.Publish(1)
+			}
+
+			assert.Equal(
+				[]delivery{
+					{reflect.TypeOf(0), "func", nil},
+					{reflect.TypeOf(0), "chan", nil},
+					{reflect.TypeOf(0), "func", expectedErr},
+				},
+				deliveries,
+			)
+		})
+	}
+}
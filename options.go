@@ -0,0 +1,314 @@
+package hub
+
+import (
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Option is a configurable option passed to New
+type Option func(*hub)
+
+// WithPanicPolicy sets the policy for listeners that panic during delivery.  The default is Propagate,
+// which lets the panic escape the call to Publish.
+func WithPanicPolicy(pp PanicPolicy) Option {
+	return func(h *hub) {
+		h.panicPolicy = pp
+	}
+}
+
+// WithLogger sets the logger used for diagnostics, such as panics recovered under RecoverAndLog.
+// If unset or nil, the standard library's default logger is used.
+func WithLogger(l *log.Logger) Option {
+	return func(h *hub) {
+		h.logger = l
+	}
+}
+
+// WithUnhandled sets a hook that is invoked with any published event that matched no sinks.
+// A fallback passed to PublishOrElse takes precedence over this hook.
+func WithUnhandled(f func(interface{})) Option {
+	return func(h *hub) {
+		h.onUnhandled = f
+	}
+}
+
+// WithErrorHandler sets a function that receives errors which occur during delivery, such as encoding failures in
+// a sink created with SubscribeWriter or errors returned by listeners subscribed with SubscribeErr.  By default,
+// listener errors are logged and other errors are ignored.  PublishGroup returns listener errors rather than
+// passing them here.
+func WithErrorHandler(f func(error)) Option {
+	return func(h *hub) {
+		h.onError = f
+	}
+}
+
+// WithDedup causes a hub to detect duplicate subscriptions.  When a listener is subscribed that is
+// identical to one already registered for the same event type, the existing subscription is returned
+// rather than adding a second sink.  Channels, pointer receivers, and other comparable listeners are identical
+// when they compare equal.  Functions are identical only when they are the same closure instance, so two
+// separate bound method values of the same method are never considered duplicates.
+//
+// Without this option, duplicate subscriptions are allowed and each one receives its own copy of every event.
+func WithDedup() Option {
+	return func(h *hub) {
+		h.dedup = true
+	}
+}
+
+// WithNoDuplicates causes a hub to reject duplicate subscriptions with ErrDuplicateListener.  Listeners are
+// identical under the same rules as WithDedup, but rather than silently reusing the existing subscription, the
+// attempt fails.  This is the strict counterpart to WithDedup, useful in tests and during development to catch
+// accidental double registration.  If both options are used, this one takes precedence.
+func WithNoDuplicates() Option {
+	return func(h *hub) {
+		h.noDuplicates = true
+	}
+}
+
+// WithDefensiveCopy causes a hub to deliver each sink its own copy of an event, so that listeners
+// cannot interfere with each other by mutating a shared event.  Pointers, slices, and maps within the
+// event are cloned up to the depth set by WithCopyDepth, which defaults to DefaultCopyDepth.  A pointer
+// event has its pointee cloned.  Events that implement Cloner are copied by their Clone method instead.
+//
+// This option is opt-in because copying costs reflection work and allocations for every sink on every publish.
+// Unexported struct fields, channels, and functions are never cloned.
+func WithDefensiveCopy() Option {
+	return func(h *hub) {
+		h.defensiveCopy = true
+		if h.copyDepth == 0 {
+			h.copyDepth = DefaultCopyDepth
+		}
+	}
+}
+
+// WithCopyDepth sets the number of levels of pointers, slices, and maps cloned by WithDefensiveCopy.
+// A depth of 0 or less makes shallow copies.  This option has no effect unless WithDefensiveCopy is also used.
+func WithCopyDepth(depth int) Option {
+	return func(h *hub) {
+		if depth < 1 {
+			depth = -1
+		}
+
+		h.copyDepth = depth
+	}
+}
+
+// WithConsumerWaitGroup sets the WaitGroup that tracks goroutines consuming events, typically goroutines
+// ranging over subscribed channels.  CloseAndDrain waits on this WaitGroup.  Callers are responsible for
+// calling Add and Done on the WaitGroup as consumers start and finish.
+func WithConsumerWaitGroup(wg *sync.WaitGroup) Option {
+	return func(h *hub) {
+		h.consumers = wg
+	}
+}
+
+// WithObserver sets the instrumentation callbacks for a hub
+func WithObserver(o Observer) Option {
+	return func(h *hub) {
+		h.observer = o
+	}
+}
+
+// WithSlowThreshold sets the duration above which a single sink delivery is reported to
+// Observer.OnSlowListener.  A threshold of 0 or less disables slow listener detection, which is the default.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(h *hub) {
+		h.slowThreshold = d
+	}
+}
+
+// WithAuditLog sets a function that receives a structured AuditEntry each time a subscription is added
+// or cancelled, including cancellations caused by Close.  The function is invoked synchronously, outside
+// of any internal lock.
+func WithAuditLog(f func(AuditEntry)) Option {
+	return func(h *hub) {
+		h.auditLog = f
+	}
+}
+
+// WithDeliveryOrder sets the order in which the listeners for an event type receive each event.  The default
+// is FIFO, which delivers in subscription order.  LIFO delivers to the most recently subscribed listener first.
+// In either case, catch-all listeners receive an event after the listeners registered for its specific type.
+func WithDeliveryOrder(order Order) Option {
+	return func(h *hub) {
+		h.order = order
+	}
+}
+
+// WithSinkKindOrder delivers each event to the listeners for a type grouped by kind, in the order the kinds are
+// given.  For example, []SinkKind{ChanSink, FuncSink} notifies channel listeners, whose sends are typically quick,
+// before function listeners that may be slow.  Kinds that are not given are delivered after those that are, and a
+// kind given more than once keeps its first position.  Within each kind, listeners receive events in the order set
+// by WithDeliveryOrder.  Listeners are arranged by kind as they subscribe, so grouping adds no cost to publishing.
+//
+// Grouping applies separately to each set of listeners that WithDeliveryOrder orders, so catch-all listeners
+// still receive an event after the listeners registered for its specific type.  An empty order, which is the
+// default, leaves delivery in subscription order.
+func WithSinkKindOrder(kinds []SinkKind) Option {
+	return func(h *hub) {
+		h.kindRanks = nil
+		for _, kind := range kinds {
+			if h.kindRanks == nil {
+				h.kindRanks = make(map[SinkKind]int, len(kinds))
+			}
+
+			// repeated kinds keep their first position, and ranks stay dense so that kinds
+			// which are not given rank after all of those that are
+			if _, ok := h.kindRanks[kind]; !ok {
+				h.kindRanks[kind] = len(h.kindRanks)
+			}
+		}
+	}
+}
+
+// WithShutdownOrder sets the order in which Close and CancelType cancel the subscriptions they remove,
+// which is also the order in which afterCancel closures run, including the channel closures added by
+// WithCloseOnCancel.  The default is FIFO, which cancels subscriptions in the order they were made.  LIFO
+// cancels the most recent subscription first, mirroring the order of deferred calls.
+//
+// Subscriptions are ordered across event types, so the sequence is the same regardless of how many types
+// are involved.  Consumers tracked by WithConsumerWaitGroup still finish in whatever order they finish.
+func WithShutdownOrder(order Order) Option {
+	return func(h *hub) {
+		h.shutdownOrder = order
+	}
+}
+
+// WithStrategy sets the default Strategy that Publish uses to deliver events.  The default is Sync.  With Async,
+// Publish behaves like PublishAsync, so events may be dropped according to WithDropWhenFull.
+//
+// Only Publish consults the strategy.  The other publish methods always deliver as they document, e.g. PublishOK
+// and PublishOrElse are synchronous so that they can report whether an event was handled, and PublishAsync is
+// asynchronous.
+func WithStrategy(s Strategy) Option {
+	return func(h *hub) {
+		h.strategy = s
+	}
+}
+
+// WithTypeStrategy overrides the Strategy that Publish uses for a single event type.  This allows, for example,
+// commands that must be validated before Publish returns to be delivered synchronously, while notifications are
+// delivered by the workers.  A per-type strategy takes precedence over WithStrategy, regardless of the order in
+// which the options are supplied.  Supplying this option again for the same type replaces the earlier strategy.
+func WithTypeStrategy(eventType reflect.Type, s Strategy) Option {
+	return func(h *hub) {
+		if eventType == nil {
+			return
+		}
+
+		if h.typeStrategies == nil {
+			h.typeStrategies = make(map[reflect.Type]Strategy)
+		}
+
+		h.typeStrategies[eventType] = s
+	}
+}
+
+// WithUnregisteredPolicy enables strict event type registration, determining how the hub reacts when an event is
+// published whose type has not been declared with RegisterEventType or RegisterEventTypeNamed.  Such an event differs
+// from one that merely has no subscribers, which is handled by WithUnhandled.  A typical migration starts with
+// LogUnregistered in production while types are declared, then moves to PanicUnregistered in tests.
+//
+// The policy applies to every publish method.  For PublishAsync, the check happens on a worker goroutine, where a
+// panic is recovered and logged like any other asynchronous panic.  Events forwarded from
+// another hub in a hierarchy created with NewChild are not checked again.  Dropped events are not counted in Stats.
+func WithUnregisteredPolicy(up UnregisteredPolicy) Option {
+	return func(h *hub) {
+		h.unregisteredPolicy = up
+	}
+}
+
+// WithRejectInvalid prevents the delivery of events that fail validation.  See RegisterValidator.  A rejected
+// event is reported only to the error handler, and not to any unhandled hook.  Publish methods that report an
+// outcome, such as PublishOK, report that the event was not handled, and PublishGroup returns the error instead.
+// A rejected event passed to PublishSticky is not retained.
+func WithRejectInvalid() Option {
+	return func(h *hub) {
+		h.rejectInvalid = true
+	}
+}
+
+// WithPausePolicy sets what happens to events published while the hub is paused.  See Pause.  The default is
+// PauseBuffer.
+func WithPausePolicy(pp PausePolicy) Option {
+	return func(h *hub) {
+		h.pause.policy = pp
+	}
+}
+
+// WithPauseCapacity sets the maximum number of events buffered while the hub is paused under the PauseBuffer
+// policy.  A capacity less than 1 uses DefaultPauseCapacity.
+func WithPauseCapacity(capacity int) Option {
+	return func(h *hub) {
+		h.pause.capacity = capacity
+	}
+}
+
+// WithInitialBuckets sizes the subscription map of a hub created by New for at least n event types.  Each
+// subscription to a new event type replaces that map with a copy, and this hint sizes every copy for n event
+// types rather than for exactly the number in use.  Applications that register hundreds of event types at
+// startup can compare BenchmarkColdStart with and without the hint.  The hint has no effect on a hub created by
+// NewConcurrent, or when n is less than 1.
+func WithInitialBuckets(n int) Option {
+	return func(h *hub) {
+		if cr, ok := h.subscriptions.(*cowRegistry); ok && n > 0 {
+			cr.capacity = n
+		}
+	}
+}
+
+// WithDrainTimeout bounds how long a subscription made with WithMailbox and WithDrainOnCancel delivers its
+// remaining events after it is cancelled.  A timeout of 0 or less uses DefaultDrainTimeout.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(h *hub) {
+		h.drainTimeout = d
+	}
+}
+
+// WithWorkers sets the number of goroutines that deliver events passed to PublishAsync.  The default,
+// and the value used for any n less than 1, is a single worker, which preserves publish order.
+func WithWorkers(n int) Option {
+	return func(h *hub) {
+		h.workers = n
+	}
+}
+
+// WithGlobalQueue bounds the asynchronous queue to the given number of pending deliveries across all sinks.
+// An event that matches three sinks, for example, counts as three pending deliveries.  When the queue is full,
+// PublishAsync blocks until enough deliveries finish, unless WithDropWhenFull is also used.  A capacity of 0
+// or less leaves the queue unbounded, which is the default.
+func WithGlobalQueue(capacity int) Option {
+	return func(h *hub) {
+		h.queueCapacity = capacity
+	}
+}
+
+// WithDropWhenFull causes PublishAsync to drop events rather than block when the queue set by
+// WithGlobalQueue is full
+func WithDropWhenFull() Option {
+	return func(h *hub) {
+		h.dropWhenFull = true
+	}
+}
+
+// WithChannelSendTimeout bounds how long delivery to a channel listener may block.  If a channel cannot accept
+// an event within d, the event is dropped for that channel, counted in Stats.DroppedCount, and delivery moves on
+// to the remaining sinks.  Function and method listeners are unaffected.  A timeout of 0 or less blocks
+// indefinitely, which is the default.
+func WithChannelSendTimeout(d time.Duration) Option {
+	return func(h *hub) {
+		h.channelSendTimeout = d
+	}
+}
+
+// WithClosedChannelPolicy sets the policy for channel listeners whose channels are closed without cancelling their
+// subscriptions.  The default is ClosedChannelPanic, under which such a send panics as it always has.  The other
+// policies drop the event for that channel, count it in Stats.DroppedCount, and let delivery continue with the
+// remaining sinks.
+func WithClosedChannelPolicy(ccp ClosedChannelPolicy) Option {
+	return func(h *hub) {
+		h.closedChannelPolicy = ccp
+	}
+}
@@ -0,0 +1,58 @@
+package hub
+
+import "sort"
+
+// Order describes the sequence in which a hub visits a set of subscriptions
+type Order int
+
+const (
+	// FIFO visits subscriptions in the order they were made.  This is the default.
+	FIFO Order = iota
+
+	// LIFO visits the most recently made subscription first
+	LIFO
+)
+
+// String returns a human-readable name for this order
+func (o Order) String() string {
+	switch o {
+	case FIFO:
+		return "FIFO"
+	case LIFO:
+		return "LIFO"
+	default:
+		return "Order(invalid)"
+	}
+}
+
+// sort arranges subscriptions in this order, according to when they were made.  ids are assigned in
+// subscription order, so comparing them suffices.
+func (o Order) sort(subs []*Subscription) {
+	sort.Slice(subs, func(i, j int) bool {
+		if o == LIFO {
+			return subs[i].id > subs[j].id
+		}
+
+		return subs[i].id < subs[j].id
+	})
+}
+
+// kindRank returns the rank of a sink within its bucket when this hub groups listeners by kind.  buckets keep
+// their subscriptions sorted by rank, and are visited in reverse for LIFO, so ranks are negated there.  kinds not
+// given to WithSinkKindOrder rank after every kind that was.  without grouping, every sink has rank 0.
+func (h *hub) kindRank(s sink) int {
+	if h.kindRanks == nil {
+		return 0
+	}
+
+	r, ok := h.kindRanks[kindOf(s)]
+	if !ok {
+		r = len(h.kindRanks)
+	}
+
+	if h.order == LIFO {
+		return -r
+	}
+
+	return r
+}
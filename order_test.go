@@ -0,0 +1,170 @@
+package hub
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDeliveryOrder(t *testing.T, order Order, expected []string) {
+	var (
+		assert = assert.New(t)
+		h      = New(WithDeliveryOrder(order))
+		actual []string
+	)
+
+	Must(h.SubscribeAll(func(interface{}) { actual = append(actual, "all") }))
+	Must(h.Subscribe(func(int) { actual = append(actual, "first") }))
+	Must(h.Subscribe(func(int) { actual = append(actual, "second") }))
+	Must(h.Subscribe(func(int) { actual = append(actual, "third") }))
+
+	h.Publish(1)
+	assert.Equal(expected, actual)
+}
+
+func TestDeliveryOrder(t *testing.T) {
+	t.Run("FIFO", func(t *testing.T) {
+		testDeliveryOrder(t, FIFO, []string{"first", "second", "third", "all"})
+	})
+
+	t.Run("LIFO", func(t *testing.T) {
+		testDeliveryOrder(t, LIFO, []string{"third", "second", "first", "all"})
+	})
+}
+
+func testShutdownOrder(t *testing.T, options []Option, reverse bool) {
+	var (
+		assert = assert.New(t)
+		h      = New(options...)
+
+		names  = []string{"first", "second", "third", "fourth", "fifth"}
+		actual []string
+	)
+
+	// subscriptions span several event types, so that map iteration order would be visible
+	for i, name := range names {
+		name := name
+		l := []interface{}{func(int) {}, func(string) {}, func(float64) {}}[i%3]
+		Must(h.Subscribe(l, func() { actual = append(actual, name) }))
+	}
+
+	if reverse {
+		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+			names[i], names[j] = names[j], names[i]
+		}
+	}
+
+	h.Close()
+	assert.Equal(names, actual)
+
+	// CancelType follows the same order
+	h = New(options...)
+	actual = nil
+	Must(h.Subscribe(func(int) {}, func() { actual = append(actual, "first") }))
+	Must(h.Subscribe(func(int) {}, func() { actual = append(actual, "second") }))
+	h.CancelType(reflect.TypeOf(0))
+	if reverse {
+		assert.Equal([]string{"second", "first"}, actual)
+	} else {
+		assert.Equal([]string{"first", "second"}, actual)
+	}
+}
+
+func TestShutdownOrder(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		testShutdownOrder(t, nil, false)
+	})
+
+	t.Run("FIFO", func(t *testing.T) {
+		testShutdownOrder(t, []Option{WithShutdownOrder(FIFO)}, false)
+	})
+
+	t.Run("LIFO", func(t *testing.T) {
+		testShutdownOrder(t, []Option{WithShutdownOrder(LIFO)}, true)
+	})
+}
+
+// kindOrderListener is a method listener that records its name
+type kindOrderListener struct {
+	name   string
+	actual *[]string
+}
+
+func (kol kindOrderListener) On(int) {
+	*kol.actual = append(*kol.actual, kol.name)
+}
+
+func testSinkKindOrder(t *testing.T, options []Option, expected []string) {
+	var (
+		assert = assert.New(t)
+		h      = New(options...)
+
+		c      = make(chan int, 1)
+		actual []string
+	)
+
+	// function listeners record whether the channel has already received the event
+	funcListener := func(name string) func(int) {
+		return func(int) {
+			actual = append(actual, fmt.Sprintf("%s:%d", name, len(c)))
+		}
+	}
+
+	Must(h.SubscribeAll(func(interface{}) { actual = append(actual, "all") }))
+	Must(h.Subscribe(funcListener("f1")))
+	Must(h.Subscribe(kindOrderListener{name: "m1", actual: &actual}))
+	Must(h.Subscribe(c))
+	Must(h.Subscribe(funcListener("f2")))
+	Must(h.Subscribe(kindOrderListener{name: "m2", actual: &actual}))
+
+	h.Publish(1)
+	assert.Equal(expected, actual)
+}
+
+func TestSinkKindOrder(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		testSinkKindOrder(t, nil, []string{"f1:0", "m1", "f2:1", "m2", "all"})
+	})
+
+	t.Run("FIFO", func(t *testing.T) {
+		testSinkKindOrder(
+			t,
+			[]Option{WithSinkKindOrder([]SinkKind{MethodSink, ChanSink, MethodSink})},
+			[]string{"m1", "m2", "f1:1", "f2:1", "all"},
+		)
+	})
+
+	t.Run("LIFO", func(t *testing.T) {
+		testSinkKindOrder(
+			t,
+			[]Option{WithDeliveryOrder(LIFO), WithSinkKindOrder([]SinkKind{ChanSink, FuncSink})},
+			[]string{"f2:1", "f1:1", "m2", "m1", "all"},
+		)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		// kinds that are not given still rank after every kind that is, even when a kind is repeated
+		testSinkKindOrder(
+			t,
+			[]Option{WithSinkKindOrder([]SinkKind{ChanSink, ChanSink, FuncSink})},
+			[]string{"f1:1", "f2:1", "m1", "m2", "all"},
+		)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		testSinkKindOrder(
+			t,
+			[]Option{WithSinkKindOrder([]SinkKind{ChanSink}), WithSinkKindOrder(nil)},
+			[]string{"f1:0", "m1", "f2:1", "m2", "all"},
+		)
+	})
+}
+
+func TestOrderString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("FIFO", FIFO.String())
+	assert.Equal("LIFO", LIFO.String())
+	assert.Equal("Order(invalid)", Order(-1).String())
+}
@@ -0,0 +1,54 @@
+package hub
+
+// PanicPolicy describes how a hub reacts when a listener panics during event delivery.
+// The policy is set with WithPanicPolicy and applies to every sink that receives an event.
+type PanicPolicy int
+
+const (
+	// Propagate lets a listener's panic escape the call to Publish.  Sinks after the panicking
+	// listener do not receive the event.  This is the default policy.
+	Propagate PanicPolicy = iota
+
+	// Recover silently recovers a listener's panic.  Delivery continues with the remaining sinks,
+	// and Publish returns normally.
+	Recover
+
+	// RecoverAndLog behaves like Recover, but also writes the recovered value to the hub's logger.
+	RecoverAndLog
+
+	// Repanic recovers each listener's panic so that every remaining sink still receives the event.
+	// After delivery completes, Publish panics with the first recovered value.
+	Repanic
+)
+
+// String returns a human-readable name for this policy
+func (pp PanicPolicy) String() string {
+	switch pp {
+	case Propagate:
+		return "Propagate"
+	case Recover:
+		return "Recover"
+	case RecoverAndLog:
+		return "RecoverAndLog"
+	case Repanic:
+		return "Repanic"
+	default:
+		return "PanicPolicy(invalid)"
+	}
+}
+
+// trySend sends a message to a subscription, recovering any panic.  The panicked flag is used rather than
+// checking the recovered value against nil, since a listener may call panic(nil).  The proceed flag is the
+// result of sendTo, and is true when a panic was recovered.
+func (h *hub) trySend(s *Subscription, m message) (proceed bool, r interface{}, panicked bool) {
+	proceed, panicked = true, true
+	defer func() {
+		if panicked {
+			r = recover()
+		}
+	}()
+
+	proceed = h.sendTo(s, m)
+	panicked = false
+	return
+}
@@ -0,0 +1,88 @@
+package hub
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panicHub creates a hub with the given policy and three listeners, the middle of which panics.
+// The returned slice records which listeners were invoked.
+func panicHub(t *testing.T, options ...Option) (Dispatcher, *[]int) {
+	var (
+		require = require.New(t)
+		h       = New(options...)
+		called  []int
+	)
+
+	_, err := h.Subscribe(func(int) { called = append(called, 1) })
+	require.NoError(err)
+
+	_, err = h.Subscribe(func(int) {
+		called = append(called, 2)
+		panic("expected")
+	})
+
+	require.NoError(err)
+
+	_, err = h.Subscribe(func(int) { called = append(called, 3) })
+	require.NoError(err)
+
+	return h, &called
+}
+
+func testPanicPolicyPropagate(t *testing.T) {
+	assert := assert.New(t)
+	h, called := panicHub(t)
+
+	assert.PanicsWithValue("expected", func() { h.Publish(1) })
+	assert.Equal([]int{1, 2}, *called)
+}
+
+func testPanicPolicyRecover(t *testing.T) {
+	assert := assert.New(t)
+	h, called := panicHub(t, WithPanicPolicy(Recover))
+
+	assert.NotPanics(func() { h.Publish(1) })
+	assert.Equal([]int{1, 2, 3}, *called)
+}
+
+func testPanicPolicyRecoverAndLog(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		output bytes.Buffer
+	)
+
+	h, called := panicHub(t, WithPanicPolicy(RecoverAndLog), WithLogger(log.New(&output, "", 0)))
+
+	assert.NotPanics(func() { h.Publish(1) })
+	assert.Equal([]int{1, 2, 3}, *called)
+	assert.Contains(output.String(), "expected")
+}
+
+func testPanicPolicyRepanic(t *testing.T) {
+	assert := assert.New(t)
+	h, called := panicHub(t, WithPanicPolicy(Repanic))
+
+	assert.PanicsWithValue("expected", func() { h.Publish(1) })
+	assert.Equal([]int{1, 2, 3}, *called)
+}
+
+func TestPanicPolicy(t *testing.T) {
+	t.Run("Propagate", testPanicPolicyPropagate)
+	t.Run("Recover", testPanicPolicyRecover)
+	t.Run("RecoverAndLog", testPanicPolicyRecoverAndLog)
+	t.Run("Repanic", testPanicPolicyRepanic)
+}
+
+func TestPanicPolicyString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("Propagate", Propagate.String())
+	assert.Equal("Recover", Recover.String())
+	assert.Equal("RecoverAndLog", RecoverAndLog.String())
+	assert.Equal("Repanic", Repanic.String())
+	assert.Equal("PanicPolicy(invalid)", PanicPolicy(-1).String())
+}
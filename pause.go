@@ -0,0 +1,132 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPauseCapacity is the number of events buffered while paused when WithPauseCapacity is not supplied
+const DefaultPauseCapacity = 1024
+
+// PausePolicy describes what happens to events published while a hub is paused
+type PausePolicy int
+
+const (
+	// PauseBuffer retains events published while paused, up to the hub's pause capacity, and delivers
+	// them when the hub resumes.  Events beyond the capacity are dropped.  This is the default.
+	PauseBuffer PausePolicy = iota
+
+	// PauseDrop drops every event published while paused
+	PauseDrop
+)
+
+// String returns a human-readable name for this policy
+func (pp PausePolicy) String() string {
+	switch pp {
+	case PauseBuffer:
+		return "PauseBuffer"
+	case PauseDrop:
+		return "PauseDrop"
+	default:
+		return "PausePolicy(invalid)"
+	}
+}
+
+// pausedEvent is an event buffered while a hub was paused
+type pausedEvent struct {
+	eventType reflect.Type
+	m         message
+	fallback  func(interface{})
+}
+
+// pauseState holds the state used by Pause and Resume
+type pauseState struct {
+	// paused is nonzero from the time Pause is called until Resume has delivered every buffered event.
+	// it is accessed atomically, so that publishing while not paused costs a single load.
+	paused uint32
+
+	lock     sync.Mutex
+	policy   PausePolicy
+	capacity int
+	buffer   []pausedEvent
+
+	// requested is true between calls to Pause and Resume, and draining is true while a call to Resume
+	// is delivering buffered events.  both are guarded by lock.
+	requested bool
+	draining  bool
+}
+
+func (h *hub) Pause() {
+	h.pause.lock.Lock()
+	h.pause.requested = true
+	atomic.StoreUint32(&h.pause.paused, 1)
+	h.pause.lock.Unlock()
+}
+
+func (h *hub) Resume() {
+	h.pause.lock.Lock()
+	h.pause.requested = false
+	if h.pause.draining {
+		// another call to Resume is already delivering, and will deliver everything buffered
+		h.pause.lock.Unlock()
+		return
+	}
+
+	h.pause.draining = true
+	for {
+		buffered := h.pause.buffer
+		h.pause.buffer = nil
+		if len(buffered) == 0 || h.pause.requested {
+			// either everything has been delivered, or Pause was called again during delivery
+			h.pause.buffer = buffered
+			h.pause.draining = false
+			if !h.pause.requested {
+				atomic.StoreUint32(&h.pause.paused, 0)
+			}
+
+			h.pause.lock.Unlock()
+			return
+		}
+
+		h.pause.lock.Unlock()
+
+		// the hub stays paused while delivering, so that events published meanwhile are buffered behind these
+		for _, pe := range buffered {
+			if !h.intercept(0, pe.eventType, pe.m) {
+				h.unhandled(pe.m.event(), pe.fallback)
+			}
+		}
+
+		h.pause.lock.Lock()
+	}
+}
+
+// held buffers or drops an event if this hub is paused.  it returns false if the hub is not paused, in which
+// case the caller must dispatch the event itself.
+func (h *hub) held(eventType reflect.Type, m message, fallback func(interface{})) bool {
+	if atomic.LoadUint32(&h.pause.paused) == 0 {
+		return false
+	}
+
+	h.pause.lock.Lock()
+	defer h.pause.lock.Unlock()
+
+	if atomic.LoadUint32(&h.pause.paused) == 0 {
+		// Resume finished while this publish waited for the lock
+		return false
+	}
+
+	capacity := h.pause.capacity
+	if capacity < 1 {
+		capacity = DefaultPauseCapacity
+	}
+
+	if h.pause.policy == PauseDrop || len(h.pause.buffer) >= capacity {
+		atomic.AddUint64(&h.counters.dropped, 1)
+	} else {
+		h.pause.buffer = append(h.pause.buffer, pausedEvent{eventType: eventType, m: m.detach(), fallback: fallback})
+	}
+
+	return true
+}
@@ -0,0 +1,167 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPauseBuffer(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received  []interface{}
+		unhandled []interface{}
+
+		h = New(WithPauseCapacity(3), WithUnhandled(func(e interface{}) { unhandled = append(unhandled, e) }))
+	)
+
+	Must(h.Subscribe(func(e int) {
+		received = append(received, e)
+		if e == 1 {
+			// published during Resume, so delivered after everything already buffered
+			h.Publish(100)
+		}
+	}))
+
+	h.Pause()
+	h.Pause()
+	assert.False(h.PublishOK(1))
+	h.Publish("unhandled")
+	h.Publish(2)
+	h.Publish(3)
+	assert.Empty(received)
+	assert.Empty(unhandled)
+	assert.Equal(uint64(1), h.Stats().DroppedCount)
+
+	// buffered events are routed against the subscriptions at the time of delivery
+	Must(h.Subscribe(func(e string) { received = append(received, e) }))
+
+	h.Resume()
+	assert.Equal([]interface{}{1, "unhandled", 2, 100}, received)
+	assert.Empty(unhandled)
+
+	h.Resume()
+	h.Publish(4)
+	assert.Equal([]interface{}{1, "unhandled", 2, 100, 4}, received)
+}
+
+func testPauseDrop(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received  []int
+		unhandled []interface{}
+
+		h = New(WithPausePolicy(PauseDrop), WithUnhandled(func(e interface{}) { unhandled = append(unhandled, e) }))
+	)
+
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+
+	h.Pause()
+	h.Publish(1)
+	h.Publish(2.0)
+	h.Resume()
+	h.Publish(3)
+
+	assert.Equal([]int{3}, received)
+	assert.Empty(unhandled)
+	assert.Equal(uint64(2), h.Stats().DroppedCount)
+}
+
+func testPauseUnhandled(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		fallback []interface{}
+		h        = New()
+	)
+
+	h.Pause()
+	h.PublishOrElse(1, func(e interface{}) { fallback = append(fallback, e) })
+	assert.Empty(fallback)
+
+	h.Resume()
+	assert.Equal([]interface{}{1}, fallback)
+}
+
+func testPauseAgainDuringResume(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received []int
+		h        = New()
+	)
+
+	Must(h.Subscribe(func(e int) {
+		received = append(received, e)
+		if e == 1 {
+			h.Pause()
+			h.Publish(3)
+		}
+	}))
+
+	h.Pause()
+	h.Publish(1)
+	h.Publish(2)
+	h.Resume()
+	assert.Equal([]int{1, 2}, received)
+
+	// the second pause remains in effect
+	h.Publish(4)
+	assert.Equal([]int{1, 2}, received)
+
+	h.Resume()
+	assert.Equal([]int{1, 2, 3, 4}, received)
+}
+
+func testPauseGroup(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		expected = errors.New("expected")
+		received []int
+		errs     []error
+		h        = New(WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	)
+
+	Must(h.SubscribeErr(func(ctx context.Context, e int) error {
+		// a held event is delivered with the context passed to PublishGroup, not the group's own context
+		assert.NoError(ctx.Err())
+		received = append(received, e)
+		return expected
+	}))
+
+	h.Pause()
+	assert.NoError(h.PublishGroup(context.Background(), 1))
+	assert.Empty(received)
+
+	// once PublishGroup has returned, a held event is delivered as Publish would deliver it
+	h.Resume()
+	assert.Equal([]int{1}, received)
+	assert.Equal([]error{expected}, errs)
+
+	h.Pause()
+	h.Resume()
+	assert.Equal(expected, h.PublishGroup(context.Background(), 2))
+	assert.Equal([]int{1, 2}, received)
+	assert.Len(errs, 1)
+}
+
+func testPausePolicyString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("PauseBuffer", PauseBuffer.String())
+	assert.Equal("PauseDrop", PauseDrop.String())
+	assert.Equal("PausePolicy(invalid)", PausePolicy(-1).String())
+}
+
+func TestPause(t *testing.T) {
+	t.Run("PolicyString", testPausePolicyString)
+	t.Run("Buffer", testPauseBuffer)
+	t.Run("Drop", testPauseDrop)
+	t.Run("Unhandled", testPauseUnhandled)
+	t.Run("AgainDuringResume", testPauseAgainDuringResume)
+	t.Run("Group", testPauseGroup)
+}
@@ -0,0 +1,83 @@
+package hub
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// presenceSlots is the number of slots in a presence filter.  it must be a power of 2.
+const presenceSlots = 256
+
+// presence is a counting filter over the routes that currently have subscriptions.  it lets route skip
+// its map lookups for event types that cannot match anything, which is the common case for types that are
+// published frequently but rarely subscribed to.
+//
+// the filter is conservative:  a true answer from absent is always correct, but a false answer only means
+// that route must look.  updates are made while holding the hub's subscribeLock, and a route is counted before
+// its first subscription becomes visible, so a publish that follows a subscribe always sees it.
+type presence struct {
+	// routes is the number of routes with at least one subscription
+	routes int32
+
+	// wide counts the routes that can match more than one event type, i.e. the catch-all route,
+	// interface types, and keyed routes
+	wide int32
+
+	// slots counts the remaining routes, which are concrete event types, by hash
+	slots [presenceSlots]int32
+}
+
+// slot returns the counter for a concrete event type.  the type's identity is its runtime pointer,
+// which is stable for the life of the process.
+func (p *presence) slot(eventType reflect.Type) *int32 {
+	ptr := reflect.ValueOf(eventType).Pointer()
+	return &p.slots[(ptr>>5^ptr>>11)&(presenceSlots-1)]
+}
+
+// counter returns the counter that tracks the given route
+func (p *presence) counter(route interface{}) *int32 {
+	if eventType, ok := route.(reflect.Type); ok && eventType.Kind() != reflect.Interface {
+		return p.slot(eventType)
+	}
+
+	return &p.wide
+}
+
+// add records that a route has gained its first subscription
+func (p *presence) add(route interface{}) {
+	atomic.AddInt32(&p.routes, 1)
+	atomic.AddInt32(p.counter(route), 1)
+}
+
+// remove records that a route has lost its last subscription
+func (p *presence) remove(route interface{}) {
+	atomic.AddInt32(p.counter(route), -1)
+	atomic.AddInt32(&p.routes, -1)
+}
+
+// clear records that every route has lost its subscriptions
+func (p *presence) clear() {
+	atomic.StoreInt32(&p.routes, 0)
+	atomic.StoreInt32(&p.wide, 0)
+	for i := range p.slots {
+		atomic.StoreInt32(&p.slots[i], 0)
+	}
+}
+
+// absent tests if a message with the given event type, which may be nil, certainly has no subscriptions
+func (p *presence) absent(eventType reflect.Type) bool {
+	switch {
+	case atomic.LoadInt32(&p.routes) == 0:
+		return true
+
+	case atomic.LoadInt32(&p.wide) > 0:
+		return false
+
+	case eventType == nil:
+		// only catch-all subscriptions receive nil events
+		return true
+
+	default:
+		return atomic.LoadInt32(p.slot(eventType)) == 0
+	}
+}
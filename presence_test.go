@@ -0,0 +1,106 @@
+package hub
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresence(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		intType    = reflect.TypeOf(0)
+		stringType = reflect.TypeOf("")
+		readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
+		p presence
+	)
+
+	assert.True(p.absent(intType))
+	assert.True(p.absent(nil))
+
+	p.add(intType)
+	assert.False(p.absent(intType))
+	assert.True(p.absent(nil))
+
+	// types that share a slot with a subscribed type are reported as possibly present
+	for i := 0; i < 2*presenceSlots; i++ {
+		arrayType := reflect.ArrayOf(i, stringType)
+		if p.slot(arrayType) == p.slot(intType) {
+			assert.False(p.absent(arrayType))
+		}
+	}
+
+	// routes that can match many types disable the per-type check
+	p.add(readerType)
+	assert.False(p.absent(stringType))
+	assert.False(p.absent(nil))
+	p.remove(readerType)
+	assert.True(p.absent(stringType))
+
+	p.add(keyedRoute{key: "key"})
+	assert.False(p.absent(stringType))
+
+	p.clear()
+	assert.True(p.absent(intType))
+	assert.True(p.absent(nil))
+}
+
+func TestPresenceHub(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	cancel := Must(h.Subscribe(func(int) {}))
+	assert.True(h.PublishOK(1))
+	assert.False(h.PublishOK("unsubscribed"))
+	assert.False(h.PublishOK(nil))
+
+	cancel()
+	assert.False(h.PublishOK(1))
+
+	cancel = Must(h.SubscribeAll(func(interface{}) {}))
+	assert.True(h.PublishOK("any"))
+	assert.True(h.PublishOK(nil))
+	cancel()
+	assert.False(h.PublishOK("any"))
+
+	Must(h.Subscribe(func(string) {}))
+	Must(h.Subscribe(func(string) {}))
+	assert.Equal(2, h.CancelType(reflect.TypeOf("")))
+	assert.False(h.PublishOK("cancelled"))
+
+	Must(h.Subscribe(func(string) {}))
+	assert.True(h.PublishOK("subscribed"))
+	h.Close()
+	assert.False(h.PublishOK("closed"))
+}
+
+// BenchmarkPublishUnsubscribed measures publishing an event type that has no listeners, both on an
+// empty hub and on a hub with listeners for other types.
+func BenchmarkPublishUnsubscribed(b *testing.B) {
+	b.Run("Empty", func(b *testing.B) {
+		h := New()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h.PublishOK(i)
+		}
+	})
+
+	b.Run("OtherTypes", func(b *testing.B) {
+		intType := reflect.TypeOf(0)
+		h := New().(*hub)
+		for i := 0; i < 100; i++ {
+			h.register(reflect.ArrayOf(i, intType), &sinkFunc{}, nil)
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h.PublishOK(i)
+		}
+	})
+}
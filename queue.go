@@ -0,0 +1,143 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// job is a single event waiting for asynchronous delivery
+type job struct {
+	eventType reflect.Type
+	m         message
+
+	// weight is the number of sinks the event matched when it was enqueued, and is
+	// the job's share of the queue's capacity
+	weight int
+
+	// urgent is set for jobs enqueued by PublishUrgent, which are taken before any other jobs
+	urgent bool
+
+	// enqueued is the time the job was enqueued.  it is only set when the observer measures queue latency.
+	enqueued time.Time
+
+	// barrier is set for the sentinel jobs enqueued by Flush.  a worker that takes a sentinel
+	// marks the barrier done, then waits for every other worker to reach it.
+	barrier *sync.WaitGroup
+
+	// probe is set for the jobs enqueued by HealthCheck.  a worker that takes a probe closes it.
+	probe chan struct{}
+}
+
+// queue is a two-level FIFO of jobs shared by a hub's workers.  urgent jobs are always taken before
+// normal jobs, and each level preserves its own order.  when bounded, its capacity is measured in pending
+// deliveries rather than events, and jobs remain pending until a worker has finished them.
+type queue struct {
+	lock     sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+
+	urgent   []job
+	jobs     []job
+	pending  int
+	capacity int
+	drop     bool
+	closed   bool
+}
+
+func newQueue(capacity int, drop bool) *queue {
+	q := &queue{
+		capacity: capacity,
+		drop:     drop,
+	}
+
+	q.notEmpty = sync.NewCond(&q.lock)
+	q.notFull = sync.NewCond(&q.lock)
+	return q
+}
+
+// full tests if a job would exceed this queue's capacity.  a job larger than the entire capacity is
+// still admitted once nothing else is pending, so that it cannot wait forever.  urgent jobs and jobs with
+// no weight, such as sentinels, are always admitted.
+func (q *queue) full(j job) bool {
+	return !j.urgent && j.weight > 0 && q.capacity > 0 && q.pending > 0 && q.pending+j.weight > q.capacity
+}
+
+// put enqueues a job, blocking or dropping it according to this queue's policy when the queue is full.
+// the current number of pending deliveries is returned, along with whether the job was accepted.
+func (q *queue) put(j job) (int, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for !q.closed && q.full(j) {
+		if q.drop {
+			return q.pending, false
+		}
+
+		q.notFull.Wait()
+	}
+
+	if q.closed {
+		return q.pending, false
+	}
+
+	if j.urgent {
+		q.urgent = append(q.urgent, j)
+	} else {
+		q.jobs = append(q.jobs, j)
+	}
+
+	q.pending += j.weight
+	q.notEmpty.Signal()
+	return q.pending, true
+}
+
+// take dequeues the next job, blocking until one is available.  the returned flag is false once
+// this queue is closed and empty.
+func (q *queue) take() (job, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.urgent) == 0 && len(q.jobs) == 0 {
+		if q.closed {
+			return job{}, false
+		}
+
+		q.notEmpty.Wait()
+	}
+
+	if len(q.urgent) > 0 {
+		return pop(&q.urgent), true
+	}
+
+	return pop(&q.jobs), true
+}
+
+// pop removes the first job from a nonempty slice of jobs
+func pop(jobs *[]job) job {
+	j := (*jobs)[0]
+	(*jobs)[0] = job{}
+	*jobs = (*jobs)[1:]
+	return j
+}
+
+// done marks a job taken from this queue as finished, releasing its share of the capacity.
+// the remaining number of pending deliveries is returned.
+func (q *queue) done(j job) int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.pending -= j.weight
+	q.notFull.Broadcast()
+	return q.pending
+}
+
+// close prevents any further jobs from being enqueued.  workers finish any jobs already enqueued.
+func (q *queue) close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
@@ -0,0 +1,78 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		q      = newQueue(3, true)
+	)
+
+	// a job larger than the capacity is admitted when nothing is pending
+	depth, ok := q.put(job{weight: 5})
+	assert.True(ok)
+	assert.Equal(5, depth)
+
+	depth, ok = q.put(job{weight: 1})
+	assert.False(ok)
+	assert.Equal(5, depth)
+
+	j, ok := q.take()
+	assert.True(ok)
+	assert.Equal(5, j.weight)
+	assert.Equal(0, q.done(j))
+
+	depth, ok = q.put(job{weight: 2})
+	assert.True(ok)
+	assert.Equal(2, depth)
+
+	q.close()
+	_, ok = q.put(job{weight: 1})
+	assert.False(ok)
+
+	// remaining jobs are still handed out after closing
+	j, ok = q.take()
+	assert.True(ok)
+	assert.Equal(2, j.weight)
+
+	_, ok = q.take()
+	assert.False(ok)
+}
+
+func TestQueueUrgent(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		q      = newQueue(2, true)
+	)
+
+	q.put(job{weight: 1})
+	q.put(job{weight: 1})
+
+	// urgent jobs are admitted even when the queue is full
+	depth, ok := q.put(job{weight: 1, urgent: true})
+	assert.True(ok)
+	assert.Equal(3, depth)
+
+	_, ok = q.put(job{weight: 2, urgent: true})
+	assert.True(ok)
+
+	// urgent jobs are taken first, and each level is FIFO
+	var (
+		weights []int
+		urgent  []bool
+	)
+
+	for i := 0; i < 4; i++ {
+		j, ok := q.take()
+		assert.True(ok)
+		weights = append(weights, j.weight)
+		urgent = append(urgent, j.urgent)
+	}
+
+	assert.Equal([]int{1, 2, 1, 1}, weights)
+	assert.Equal([]bool{true, true, false, false}, urgent)
+}
@@ -0,0 +1,59 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+)
+
+// channelRefs counts the live subscriptions to a hub made with WithCloseOnCancel for each channel, so that a
+// channel shared by several such subscriptions is closed only when the last of them is cancelled.  channels are
+// keyed by their send-only view, so a bidirectional channel and a send-only view of it count as the same channel.
+// since the map holds the channel itself rather than its address, a channel that is garbage collected without
+// being cancelled can never pass its count on to a new channel at the same address.
+type channelRefs struct {
+	lock   sync.Mutex
+	counts map[interface{}]int
+}
+
+// channelKey returns the send-only view of a channel, which is comparable and identical for every view of it
+func channelKey(c reflect.Value) interface{} {
+	return c.Convert(reflect.ChanOf(reflect.SendDir, c.Type().Elem())).Interface()
+}
+
+// acquire records a new subscription that closes the given channel on cancellation
+func (cr *channelRefs) acquire(c reflect.Value) {
+	cr.lock.Lock()
+	if cr.counts == nil {
+		cr.counts = make(map[interface{}]int)
+	}
+
+	cr.counts[channelKey(c)]++
+	cr.lock.Unlock()
+}
+
+// release records the cancellation of a subscription that closes the given channel, closing it if
+// that subscription was the last
+func (cr *channelRefs) release(c reflect.Value) {
+	cr.lock.Lock()
+	key := channelKey(c)
+	cr.counts[key]--
+	last := cr.counts[key] <= 0
+	if last {
+		delete(cr.counts, key)
+	}
+
+	cr.lock.Unlock()
+	if last {
+		closeChannel(c)
+	}
+}
+
+// closeChannel closes a channel.  a consumer may have already closed the channel itself, which
+// WithClosedChannelPolicy allows, in which case it is left as is.
+func closeChannel(c reflect.Value) {
+	defer func() {
+		recover()
+	}()
+
+	c.Close()
+}
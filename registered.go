@@ -0,0 +1,90 @@
+package hub
+
+import "reflect"
+
+// UnregisteredPolicy describes how a hub reacts to the publication of an event whose type has not been
+// registered with RegisterEventType
+type UnregisteredPolicy int
+
+const (
+	// AllowUnregistered performs no checks, so every event type may be published.  This is the default.
+	AllowUnregistered UnregisteredPolicy = iota
+
+	// IgnoreUnregistered silently drops events of unregistered types
+	IgnoreUnregistered
+
+	// LogUnregistered logs each event of an unregistered type, then delivers it normally
+	LogUnregistered
+
+	// PanicUnregistered panics when an event of an unregistered type is published, before anything is delivered
+	PanicUnregistered
+)
+
+// String returns a human-readable name for this policy
+func (up UnregisteredPolicy) String() string {
+	switch up {
+	case AllowUnregistered:
+		return "AllowUnregistered"
+	case IgnoreUnregistered:
+		return "IgnoreUnregistered"
+	case LogUnregistered:
+		return "LogUnregistered"
+	case PanicUnregistered:
+		return "PanicUnregistered"
+	default:
+		return "UnregisteredPolicy(invalid)"
+	}
+}
+
+// loadRegistered returns the set of registered event types, which may be nil
+func (h *hub) loadRegistered() map[reflect.Type]bool {
+	registered, _ := h.registered.Load().(map[reflect.Type]bool)
+	return registered
+}
+
+// registerType adds an event type to the registered set.  it must be called while holding subscribeLock.
+func (h *hub) registerType(eventType reflect.Type) {
+	current := h.loadRegistered()
+	if current[eventType] {
+		return
+	}
+
+	updated := make(map[reflect.Type]bool, len(current)+1)
+	for k := range current {
+		updated[k] = true
+	}
+
+	updated[eventType] = true
+	h.registered.Store(updated)
+}
+
+func (h *hub) RegisterEventType(eventType reflect.Type) error {
+	if eventType == nil {
+		return ErrInvalidEventType
+	}
+
+	h.subscribeLock.Lock()
+	h.registerType(eventType)
+	h.subscribeLock.Unlock()
+	return nil
+}
+
+// known applies this hub's UnregisteredPolicy to an event, returning false if the event must be dropped.
+// nil events have no type to register, so they are always known.
+func (h *hub) known(eventType reflect.Type) bool {
+	if h.unregisteredPolicy == AllowUnregistered || eventType == nil || h.loadRegistered()[eventType] {
+		return true
+	}
+
+	switch h.unregisteredPolicy {
+	case IgnoreUnregistered:
+		return false
+
+	case PanicUnregistered:
+		panic("hub: published an event of unregistered type " + eventType.String())
+
+	default:
+		h.logf("published an event of unregistered type %v", eventType)
+		return true
+	}
+}
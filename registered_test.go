@@ -0,0 +1,82 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUnregisteredPolicyString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("AllowUnregistered", AllowUnregistered.String())
+	assert.Equal("IgnoreUnregistered", IgnoreUnregistered.String())
+	assert.Equal("LogUnregistered", LogUnregistered.String())
+	assert.Equal("PanicUnregistered", PanicUnregistered.String())
+	assert.Equal("UnregisteredPolicy(invalid)", UnregisteredPolicy(-1).String())
+}
+
+func testUnregisteredPolicyPublish(t *testing.T) {
+	for _, record := range []struct {
+		policy    UnregisteredPolicy
+		delivered bool
+		logged    string
+	}{
+		{policy: AllowUnregistered, delivered: true},
+		{policy: IgnoreUnregistered},
+		{policy: LogUnregistered, delivered: true, logged: "published an event of unregistered type float64\npublished an event of unregistered type float64\n"},
+		{policy: PanicUnregistered},
+	} {
+		t.Run(record.policy.String(), func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				output    bytes.Buffer
+				received  []interface{}
+				unhandled []interface{}
+
+				h = New(
+					WithUnregisteredPolicy(record.policy),
+					WithLogger(log.New(&output, "", 0)),
+					WithUnhandled(func(e interface{}) { unhandled = append(unhandled, e) }),
+				)
+			)
+
+			Must(h.SubscribeAll(func(e interface{}) { received = append(received, e) }))
+			require.NoError(h.RegisterEventType(reflect.TypeOf(0)))
+			require.NoError(h.RegisterEventTypeNamed("string", reflect.TypeOf("")))
+			assert.Equal(ErrInvalidEventType, h.RegisterEventType(nil))
+
+			h.Publish(1)
+			h.Publish("one")
+			h.Publish(nil)
+
+			if record.policy == PanicUnregistered {
+				assert.Panics(func() { h.Publish(1.0) })
+				assert.Panics(func() { h.PublishGroup(context.Background(), 2.0) })
+			} else {
+				assert.Equal(record.delivered, h.PublishOK(1.0))
+				assert.NoError(h.PublishGroup(context.Background(), 2.0))
+			}
+
+			expected := []interface{}{1, "one", nil}
+			if record.delivered {
+				expected = append(expected, 1.0, 2.0)
+			}
+
+			assert.Equal(expected, received)
+			assert.Empty(unhandled)
+			assert.Equal(record.logged, output.String())
+		})
+	}
+}
+
+func TestUnregisteredPolicy(t *testing.T) {
+	t.Run("String", testUnregisteredPolicyString)
+	t.Run("Publish", testUnregisteredPolicyPublish)
+}
@@ -0,0 +1,181 @@
+package hub
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// registry is the storage for a hub's subscriptions, organized into buckets by route.  reads are
+// safe at any time, but mutations must only be made while holding the hub's subscribeLock.
+type registry interface {
+	// sinks returns the current subscriptions for a route.  the returned slice must not be modified.
+	sinks(route interface{}) []*Subscription
+
+	// add appends a subscription to the bucket for its route
+	add(sub *Subscription)
+
+	// remove removes a subscription from the bucket for its route, returning true if it was present
+	remove(sub *Subscription) bool
+
+	// removeRoute empties the bucket for a route, returning the subscriptions it held
+	removeRoute(route interface{}) []*Subscription
+
+	// removeAll empties every bucket, returning all the subscriptions that were held
+	removeAll() []*Subscription
+
+	// each invokes f for every route that has at least one subscription
+	each(f func(route interface{}, subs []*Subscription))
+}
+
+// find returns the subscription for the given route whose listener has the given key.
+// if there is no such subscription, or if key is nil, this function returns nil.
+func find(r registry, route interface{}, key interface{}) *Subscription {
+	for _, candidate := range r.sinks(route) {
+		if sameKey(candidate.key, key) {
+			return candidate
+		}
+	}
+
+	return nil
+}
+
+// cowRegistry is the registry used by New.  it stores a copy-on-write subscriptions map, so publishes
+// do a single atomic load followed by plain map lookups.  adding a new route clones the map.
+type cowRegistry struct {
+	current atomic.Value
+
+	// capacity is the minimum number of routes each clone is sized for, as set by WithInitialBuckets
+	capacity int
+}
+
+func (cr *cowRegistry) load() subscriptions {
+	v, _ := cr.current.Load().(subscriptions)
+	return v
+}
+
+func (cr *cowRegistry) sinks(route interface{}) []*Subscription {
+	return cr.load().sinks(route)
+}
+
+func (cr *cowRegistry) add(sub *Subscription) {
+	cr.current.Store(cr.load().add(sub, cr.capacity))
+}
+
+func (cr *cowRegistry) remove(sub *Subscription) bool {
+	// remove only updates the subscription's bucket, so there is no new snapshot to store
+	_, removed := cr.load().remove(sub)
+	return removed
+}
+
+func (cr *cowRegistry) removeRoute(route interface{}) []*Subscription {
+	// the empty bucket is discarded later, as with any other bucket that becomes empty
+	b, ok := cr.load()[route]
+	if !ok {
+		return nil
+	}
+
+	removed := b.load()
+	b.store(nil)
+	return removed
+}
+
+func (cr *cowRegistry) removeAll() []*Subscription {
+	current := cr.load()
+	cr.current.Store(make(subscriptions, cr.capacity))
+
+	var removed []*Subscription
+	for _, b := range current {
+		removed = append(removed, b.load()...)
+	}
+
+	return removed
+}
+
+func (cr *cowRegistry) each(f func(interface{}, []*Subscription)) {
+	for route, b := range cr.load() {
+		// buckets that have become empty are retained until the next new route is added
+		if subs := b.load(); len(subs) > 0 {
+			f(route, subs)
+		}
+	}
+}
+
+// concurrentRegistry is the registry used by NewConcurrent.  buckets are held in a sync.Map, so adding
+// or removing a route never copies the other routes.  empty buckets are deleted immediately.
+type concurrentRegistry struct {
+	buckets sync.Map
+}
+
+func (cr *concurrentRegistry) bucket(route interface{}) *bucket {
+	if v, ok := cr.buckets.Load(route); ok {
+		return v.(*bucket)
+	}
+
+	return nil
+}
+
+func (cr *concurrentRegistry) sinks(route interface{}) []*Subscription {
+	if b := cr.bucket(route); b != nil {
+		return b.load()
+	}
+
+	return nil
+}
+
+func (cr *concurrentRegistry) add(sub *Subscription) {
+	if b := cr.bucket(sub.route); b != nil {
+		b.add(sub)
+		return
+	}
+
+	cr.buckets.Store(sub.route, newBucket([]*Subscription{sub}))
+}
+
+func (cr *concurrentRegistry) remove(sub *Subscription) bool {
+	b := cr.bucket(sub.route)
+	if b == nil || !b.remove(sub) {
+		return false
+	}
+
+	if len(b.load()) == 0 {
+		// a concurrent publish may still hold this bucket, which is fine since it is now empty
+		cr.buckets.Delete(sub.route)
+	}
+
+	return true
+}
+
+func (cr *concurrentRegistry) removeRoute(route interface{}) []*Subscription {
+	b := cr.bucket(route)
+	if b == nil {
+		return nil
+	}
+
+	cr.buckets.Delete(route)
+	removed := b.load()
+	b.store(nil)
+	return removed
+}
+
+func (cr *concurrentRegistry) removeAll() []*Subscription {
+	var removed []*Subscription
+	cr.buckets.Range(func(route, v interface{}) bool {
+		cr.buckets.Delete(route)
+		b := v.(*bucket)
+		removed = append(removed, b.load()...)
+		b.store(nil)
+		return true
+	})
+
+	return removed
+}
+
+func (cr *concurrentRegistry) each(f func(interface{}, []*Subscription)) {
+	cr.buckets.Range(func(route, v interface{}) bool {
+		if subs := v.(*bucket).load(); len(subs) > 0 {
+			f(route, subs)
+		}
+
+		return true
+	})
+}
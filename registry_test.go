@@ -0,0 +1,224 @@
+package hub
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRegistry(t *testing.T, r registry) {
+	var (
+		assert = assert.New(t)
+
+		intType    = reflect.TypeOf(0)
+		stringType = reflect.TypeOf("")
+
+		s1 = &Subscription{id: 1, eventType: intType, route: intType, key: "s1"}
+		s2 = &Subscription{id: 2, eventType: intType, route: intType}
+		s3 = &Subscription{id: 3, eventType: stringType, route: stringType}
+		s4 = &Subscription{id: 4, eventType: stringType, route: stringType}
+	)
+
+	assert.Empty(r.sinks(intType))
+
+	r.add(s1)
+	r.add(s2)
+	r.add(s3)
+	assert.Equal([]*Subscription{s1, s2}, r.sinks(intType))
+	assert.Equal([]*Subscription{s3}, r.sinks(stringType))
+	assert.True(find(r, intType, "s1") == s1)
+	assert.Nil(find(r, intType, "missing"))
+
+	visited := make(map[interface{}]int)
+	r.each(func(route interface{}, subs []*Subscription) {
+		visited[route] = len(subs)
+	})
+
+	assert.Equal(map[interface{}]int{intType: 2, stringType: 1}, visited)
+
+	assert.True(r.remove(s3))
+	assert.False(r.remove(s3))
+	assert.Empty(r.sinks(stringType))
+
+	// empty buckets are never visited
+	visited = make(map[interface{}]int)
+	r.each(func(route interface{}, subs []*Subscription) {
+		visited[route] = len(subs)
+	})
+
+	assert.Equal(map[interface{}]int{intType: 2}, visited)
+
+	assert.ElementsMatch([]*Subscription{s1, s2}, r.removeRoute(intType))
+	assert.Empty(r.sinks(intType))
+	assert.Empty(r.removeRoute(intType))
+
+	r.add(s1)
+	r.add(s4)
+	assert.ElementsMatch([]*Subscription{s1, s4}, r.removeAll())
+	assert.Empty(r.sinks(intType))
+	assert.Empty(r.sinks(stringType))
+	assert.Empty(r.removeAll())
+}
+
+func testNewConcurrent(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = NewConcurrent()
+
+		ints    []int
+		strings []string
+	)
+
+	cancelInt := Must(h.Subscribe(func(e int) { ints = append(ints, e) }))
+	Must(h.Subscribe(func(e string) { strings = append(strings, e) }))
+	Must(h.Subscribe(func(e string) { strings = append(strings, e) }))
+
+	h.Publish(1)
+	h.Publish("one")
+	assert.Equal([]int{1}, ints)
+	assert.Equal([]string{"one", "one"}, strings)
+	assert.Equal(Stats{TotalSubscriptions: 3, EventTypeCount: 2, PublishCount: 2, TotalSubscribed: 3}, h.Stats())
+
+	cancelInt()
+	assert.Equal(2, h.CancelType(reflect.TypeOf("")))
+	assert.False(h.PublishOK(2))
+	assert.False(h.PublishOK("two"))
+	assert.Equal([]int{1}, ints)
+	assert.Equal(Stats{PublishCount: 4, TotalSubscribed: 3, TotalCancelled: 3}, h.Stats())
+
+	Must(h.Subscribe(func(e int) { ints = append(ints, e) }))
+	h.Close()
+	assert.False(h.PublishOK(3))
+	assert.Equal([]int{1}, ints)
+}
+
+func testInitialBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	h := New(WithInitialBuckets(16)).(*hub)
+	assert.Equal(16, h.subscriptions.(*cowRegistry).capacity)
+	assert.Zero(New(WithInitialBuckets(-1)).(*hub).subscriptions.(*cowRegistry).capacity)
+
+	// NewConcurrent accepts, and ignores, the hint
+	var received []int
+	c := NewConcurrent(WithInitialBuckets(16))
+	Must(c.Subscribe(func(e int) { received = append(received, e) }))
+	c.Publish(1)
+	assert.Equal([]int{1}, received)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("CopyOnWrite", func(t *testing.T) { testRegistry(t, new(cowRegistry)) })
+	t.Run("CopyOnWriteCapacity", func(t *testing.T) { testRegistry(t, &cowRegistry{capacity: 16}) })
+	t.Run("Concurrent", func(t *testing.T) { testRegistry(t, new(concurrentRegistry)) })
+	t.Run("NewConcurrent", testNewConcurrent)
+	t.Run("InitialBuckets", testInitialBuckets)
+}
+
+// benchmarkConstructors are the hub constructors compared by the registry benchmarks
+var benchmarkConstructors = []struct {
+	name string
+	new  func(...Option) Dispatcher
+}{
+	{"New", New},
+	{"NewConcurrent", NewConcurrent},
+}
+
+// BenchmarkChurn measures subscribing and cancelling listeners for event types that have no other
+// subscribers, as the number of distinct event types with subscribers grows.  This is the workload
+// that favors NewConcurrent.
+func BenchmarkChurn(b *testing.B) {
+	intType := reflect.TypeOf(0)
+	for _, c := range benchmarkConstructors {
+		for _, eventTypes := range []int{10, 100, 1000, 10000} {
+			b.Run(c.name+"/"+strconv.Itoa(eventTypes), func(b *testing.B) {
+				h := c.new().(*hub)
+				for i := 0; i < eventTypes; i++ {
+					h.register(reflect.ArrayOf(i, intType), &sinkFunc{}, nil)
+				}
+
+				// rotate through types that are otherwise unsubscribed, so each subscription adds a route
+				churnTypes := make([]reflect.Type, 16)
+				for i := range churnTypes {
+					churnTypes[i] = reflect.ArrayOf(i, reflect.TypeOf(""))
+				}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					sub, _ := h.register(churnTypes[i%len(churnTypes)], &sinkFunc{}, nil)
+					sub.Cancel()
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkRoute measures looking up the subscriptions for a published event, which is the
+// workload that favors New.
+func BenchmarkRoute(b *testing.B) {
+	intType := reflect.TypeOf(0)
+	for _, c := range benchmarkConstructors {
+		b.Run(c.name, func(b *testing.B) {
+			h := c.new().(*hub)
+			for i := 0; i < 100; i++ {
+				h.register(reflect.ArrayOf(i, intType), &sinkFunc{}, nil)
+			}
+
+			var (
+				eventType = reflect.ArrayOf(50, intType)
+				m         = message{value: reflect.New(eventType).Elem()}
+				fixed     [2][]*Subscription
+			)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.route(fixed[:0], eventType, m)
+			}
+		})
+	}
+}
+
+// BenchmarkColdStart measures the burst of subscriptions made as an application starts, with each
+// subscription adding a new event type to an empty hub.  The New constructor is measured both with and
+// without a WithInitialBuckets hint equal to the number of event types.
+func BenchmarkColdStart(b *testing.B) {
+	intType := reflect.TypeOf(0)
+	for _, c := range benchmarkConstructors {
+		for _, hint := range []bool{false, true} {
+			if hint && c.name != "New" {
+				// NewConcurrent ignores the hint
+				continue
+			}
+
+			for _, eventTypes := range []int{100, 500} {
+				var (
+					name    = c.name + "/" + strconv.Itoa(eventTypes)
+					options []Option
+				)
+
+				if hint {
+					name += "/WithInitialBuckets"
+					options = append(options, WithInitialBuckets(eventTypes))
+				}
+
+				b.Run(name, func(b *testing.B) {
+					types := make([]reflect.Type, eventTypes)
+					for i := range types {
+						types[i] = reflect.ArrayOf(i, intType)
+					}
+
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						h := c.new(options...).(*hub)
+						for _, eventType := range types {
+							h.register(eventType, &sinkFunc{}, nil)
+						}
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package hub
+
+import "reflect"
+
+var remainingType = reflect.TypeOf(Remaining(0))
+
+// Remaining is the number of events in the current PublishBatch that follow the event being delivered.  Listeners
+// receive it by declaring a second input parameter of type Remaining, e.g. func(MyEvent, Remaining), which is useful
+// for reporting progress through a batch.
+//
+// Remaining counts events, not deliveries to a particular listener, so events of other types later in the batch are
+// included.  It is 0 for the last event of a batch and for every event published by means other than PublishBatch.
+type Remaining int
+
+func (h *hub) PublishBatch(events []interface{}) {
+	for i, e := range events {
+		eventType := reflect.TypeOf(e)
+		h.countPublish(eventType)
+		h.dispatch(eventType, message{value: reflect.ValueOf(e), remaining: len(events) - i - 1}, nil)
+	}
+}
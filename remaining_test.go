@@ -0,0 +1,46 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type remainingListener struct {
+	remaining []Remaining
+}
+
+func (rl *remainingListener) OnString(e string, r Remaining) {
+	rl.remaining = append(rl.remaining, r)
+}
+
+func TestPublishBatch(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h = New()
+
+		events    []int
+		remaining []Remaining
+		rl        = new(remainingListener)
+	)
+
+	Must(h.Subscribe(func(e int, r Remaining) {
+		events = append(events, e)
+		remaining = append(remaining, r)
+	}))
+
+	Must(h.Subscribe(rl))
+
+	h.PublishBatch([]interface{}{1, "two", 3, 4})
+	assert.Equal([]int{1, 3, 4}, events)
+	assert.Equal([]Remaining{3, 1, 0}, remaining)
+	assert.Equal([]Remaining{2}, rl.remaining)
+
+	// events outside of a batch have nothing remaining
+	h.Publish(5)
+	h.PublishBatch(nil)
+	assert.Equal([]Remaining{3, 1, 0, 0}, remaining)
+	assert.Equal(uint64(4), h.PublishCounts()[reflect.TypeOf(0)])
+}
@@ -0,0 +1,131 @@
+package hub
+
+import "reflect"
+
+// Outcome describes what happened when an event was delivered to a single subscription
+type Outcome int
+
+const (
+	// Delivered indicates that the subscription's listener received the event
+	Delivered Outcome = iota
+
+	// Skipped indicates that the subscription was disabled via SetEnabled
+	Skipped
+
+	// Vetoed indicates that the subscription's interceptor stopped the event.  See WithInterceptor.
+	Vetoed
+
+	// Dropped indicates that a channel listener could not accept the event in time.  See WithChannelSendTimeout.
+	Dropped
+
+	// Errored indicates that the listener failed, either by panicking under a PanicPolicy that recovers
+	// or by returning an error to the hub, as a writer subscribed with SubscribeWriter can
+	Errored
+)
+
+// String returns a human-readable name for this outcome
+func (o Outcome) String() string {
+	switch o {
+	case Delivered:
+		return "delivered"
+	case Skipped:
+		return "skipped"
+	case Vetoed:
+		return "vetoed"
+	case Dropped:
+		return "dropped"
+	case Errored:
+		return "errored"
+	default:
+		return "Outcome(invalid)"
+	}
+}
+
+// SinkResult is the outcome of delivering an event to a single subscription
+type SinkResult struct {
+	// EventType is the subscription's event type
+	EventType reflect.Type
+
+	// Label is the label supplied via WithLabel, if any
+	Label string
+
+	// Kind is the kind of listener
+	Kind SinkKind
+
+	// Outcome is what happened to the event
+	Outcome Outcome
+
+	// Err is the error associated with an Errored outcome.  For a recovered panic, this is either the panic
+	// value itself, if it is an error, or a *PanicError.
+	Err error
+}
+
+// Report describes the delivery of a single event published via PublishReport.  Results are in delivery order.
+// Subscriptions that an interceptor's veto prevented from being visited at all do not appear.
+type Report struct {
+	Results []SinkResult
+}
+
+// Count returns the number of results with the given outcome
+func (r Report) Count(o Outcome) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Outcome == o {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (h *hub) PublishReport(e interface{}) Report {
+	var (
+		eventType = reflect.TypeOf(e)
+		report    Report
+	)
+
+	h.countPublish(eventType)
+	h.dispatch(eventType, message{value: reflect.ValueOf(e), report: &report}, nil)
+	return report
+}
+
+// track prepares a message for delivery to a single subscription.  when reporting, a fresh result
+// is attached so that sinks can record outcomes such as drops.
+func (m message) track(s *Subscription) message {
+	if m.report != nil {
+		kind, _ := describe(s.sink)
+		m.result = &SinkResult{
+			EventType: s.eventType,
+			Label:     s.label,
+			Kind:      kind,
+		}
+	}
+
+	return m
+}
+
+// fail allows a sink to record an outcome other than Delivered.  when reporting, the outcome is recorded,
+// and when publishing with PublishGroup, any error fails the group.
+func (m message) fail(outcome Outcome, err error) {
+	if m.result != nil {
+		m.result.Outcome, m.result.Err = outcome, err
+	}
+
+	if m.group != nil && err != nil {
+		m.group.fail(err)
+	}
+}
+
+// record adds this message's result to its report.  an outcome of Delivered preserves any
+// outcome already recorded by the sink via fail.
+func (m message) record(outcome Outcome, err error) {
+	if m.result == nil {
+		return
+	}
+
+	if outcome != Delivered {
+		m.fail(outcome, err)
+	}
+
+	m.report.Results = append(m.report.Results, *m.result)
+}
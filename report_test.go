@@ -0,0 +1,62 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishReport(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		full = make(chan int, 1)
+		h    = New(
+			WithPanicPolicy(Recover),
+			WithChannelSendTimeout(time.Millisecond),
+		)
+	)
+
+	_, err := h.SubscribeWith(func(int) {}, WithLabel("delivered"))
+	require.NoError(err)
+
+	disabled, err := h.SubscribeWith(func(int) {})
+	require.NoError(err)
+	disabled.SetEnabled(false)
+
+	Must(h.Subscribe(func(int) { panic("expected") }))
+	Must(h.Subscribe(full))
+	Must(h.SubscribeWriter(reflect.TypeOf(0), failingWriter{}))
+
+	h.Publish(0)
+	report := h.PublishReport(1)
+	require.Len(report.Results, 5)
+
+	assert.Equal(SinkResult{EventType: reflect.TypeOf(0), Label: "delivered", Kind: FuncSink, Outcome: Delivered}, report.Results[0])
+	assert.Equal(Skipped, report.Results[1].Outcome)
+	assert.Equal(Errored, report.Results[2].Outcome)
+	assert.Equal(&PanicError{Value: "expected"}, report.Results[2].Err)
+	assert.Equal(SinkResult{EventType: reflect.TypeOf(0), Kind: ChanSink, Outcome: Dropped}, report.Results[3])
+	assert.Equal(Errored, report.Results[4].Outcome)
+	assert.EqualError(report.Results[4].Err, "expected")
+
+	assert.Equal(1, report.Count(Delivered))
+	assert.Equal(2, report.Count(Errored))
+	assert.Equal("dropped", Dropped.String())
+
+	// a veto ends the bucket
+	h = New()
+	_, err = h.SubscribeWith(func(string) {}, WithInterceptor(func(interface{}) bool { return false }))
+	require.NoError(err)
+	Must(h.Subscribe(func(string) {}))
+
+	report = h.PublishReport("vetoed")
+	require.Len(report.Results, 1)
+	assert.Equal(Vetoed, report.Results[0].Outcome)
+
+	assert.Empty(h.PublishReport(123).Results)
+}
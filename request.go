@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+package hub
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// requestIDs is the source of correlation ids for Request
+var requestIDs uint64
+
+// Request implements request/reply over a hub.  The request is published with a new, unique Meta.CorrelationID,
+// and Request then waits for the first Resp that is published with that same correlation id.  If no such response
+// is published within the timeout, ErrTimeout is returned.  A timeout of 0 or less waits indefinitely.
+//
+// Responders subscribe to Req with a listener that accepts a Meta, then publish their response via PublishMeta
+// using the request's correlation id.  Respond does exactly this.
+func Request[Req, Resp any](h Dispatcher, req Req, timeout time.Duration) (Resp, error) {
+	var (
+		correlationID = "request-" + strconv.FormatUint(atomic.AddUint64(&requestIDs, 1), 10)
+		reply         = make(chan Resp, 1)
+		zero          Resp
+	)
+
+	cancel, err := h.Subscribe(func(resp Resp, meta Meta) {
+		if meta.CorrelationID == correlationID {
+			select {
+			case reply <- resp:
+			default:
+				// only the first response is used
+			}
+		}
+	})
+
+	if err != nil {
+		return zero, err
+	}
+
+	defer cancel()
+	h.PublishMeta(req, Meta{CorrelationID: correlationID})
+
+	if timeout <= 0 {
+		return <-reply, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-reply:
+		return resp, nil
+
+	case <-timer.C:
+		return zero, ErrTimeout
+	}
+}
+
+// Respond subscribes fn as the responder for requests of type Req made with Request.  Each response that fn
+// returns is published with the correlation id of the request it answers.
+func Respond[Req, Resp any](h Dispatcher, fn func(Req) Resp) (Cancel, error) {
+	return h.Subscribe(func(req Req, meta Meta) {
+		h.PublishMeta(fn(req), Meta{CorrelationID: meta.CorrelationID})
+	})
+}
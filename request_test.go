@@ -0,0 +1,64 @@
+//go:build go1.21
+// +build go1.21
+
+package hub
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRequestReply(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	cancel, err := Respond(h, func(req int) string { return strconv.Itoa(req * 2) })
+	require.NoError(err)
+
+	// concurrent requests each receive their own response
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := Request[int, string](h, i, time.Second)
+			assert.NoError(err)
+			assert.Equal(strconv.Itoa(i*2), resp)
+		}(i)
+	}
+
+	wg.Wait()
+	cancel()
+
+	// unrelated responses are ignored
+	h.PublishMeta("unrelated", Meta{CorrelationID: "unrelated"})
+	resp, err := Request[int, string](h, 1, 10*time.Millisecond)
+	assert.Equal(ErrTimeout, err)
+	assert.Empty(resp)
+}
+
+func testRequestInvalid(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	h.Close()
+	resp, err := Request[int, string](h, 1, time.Second)
+	assert.Equal(ErrClosed, err)
+	assert.Empty(resp)
+}
+
+func TestRequest(t *testing.T) {
+	t.Run("Reply", testRequestReply)
+	t.Run("Invalid", testRequestInvalid)
+}
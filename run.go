@@ -0,0 +1,74 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+)
+
+// Group is the subset of golang.org/x/sync/errgroup.Group that RunWith uses.  An *errgroup.Group
+// satisfies this interface, so this package does not depend on errgroup directly.
+type Group interface {
+	Go(func() error)
+}
+
+// RunWith subscribes to events of the given type and starts a goroutine in g that passes each event to handler.
+// Events are handed to the goroutine over an unbuffered channel, so handler runs for one event at a time and
+// publishers wait while it is busy.  ctx is passed to handler and is typically the context returned alongside g
+// by errgroup.WithContext.
+//
+// The goroutine exits when ctx is done, returning nil, or when handler returns an error, returning that error.
+// Either way, the subscription is cancelled as the goroutine exits, and any publish waiting to hand off an event
+// is released.  The eventType must be one that s accepts for a function listener, e.g. a concrete type.
+//
+// If an error occurs, nothing is started and the error is returned.
+func RunWith(ctx context.Context, g Group, s Subscriber, eventType reflect.Type, handler func(context.Context, interface{}) error) error {
+	if eventType == nil {
+		return ErrInvalidEventType
+	}
+
+	if handler == nil {
+		return ErrInvalidListener
+	}
+
+	var (
+		events = make(chan interface{})
+		done   = make(chan struct{})
+
+		listener = reflect.MakeFunc(
+			reflect.FuncOf([]reflect.Type{eventType}, nil, false),
+			func(args []reflect.Value) []reflect.Value {
+				select {
+				case events <- args[0].Interface():
+				case <-done:
+					// the goroutine has exited, so this event is discarded
+				}
+
+				return nil
+			},
+		)
+	)
+
+	cancel, err := s.Subscribe(listener.Interface())
+	if err != nil {
+		return err
+	}
+
+	g.Go(func() error {
+		defer cancel()
+		defer close(done)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case e := <-events:
+				if err := handler(ctx, e); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	return nil
+}
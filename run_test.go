@@ -0,0 +1,106 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGroup is a minimal stand-in for errgroup.Group
+type testGroup struct {
+	wg    sync.WaitGroup
+	lock  sync.Mutex
+	first error
+}
+
+func (tg *testGroup) Go(f func() error) {
+	tg.wg.Add(1)
+	go func() {
+		defer tg.wg.Done()
+		if err := f(); err != nil {
+			tg.lock.Lock()
+			if tg.first == nil {
+				tg.first = err
+			}
+
+			tg.lock.Unlock()
+		}
+	}()
+}
+
+func (tg *testGroup) Wait() error {
+	tg.wg.Wait()
+	return tg.first
+}
+
+func testRunWithCancel(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h           = New()
+		g           = new(testGroup)
+		ctx, cancel = context.WithCancel(context.Background())
+		received    []interface{}
+	)
+
+	require.NoError(RunWith(ctx, g, h, reflect.TypeOf(0), func(handlerCtx context.Context, e interface{}) error {
+		assert.Equal(ctx, handlerCtx)
+		received = append(received, e)
+		return nil
+	}))
+
+	h.Publish(1)
+	h.Publish(2)
+	cancel()
+	assert.NoError(g.Wait())
+	assert.Equal([]interface{}{1, 2}, received)
+
+	// the subscription is removed once the goroutine exits
+	assert.False(h.PublishOK(3))
+}
+
+func testRunWithError(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		g        = new(testGroup)
+		expected = errors.New("expected")
+	)
+
+	require.NoError(RunWith(context.Background(), g, h, reflect.TypeOf(""), func(context.Context, interface{}) error {
+		return expected
+	}))
+
+	h.Publish("fail")
+	assert.Equal(expected, g.Wait())
+	assert.False(h.PublishOK("after"))
+}
+
+func testRunWithInvalid(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		h       = New()
+		g       = new(testGroup)
+		handler = func(context.Context, interface{}) error { return nil }
+	)
+
+	assert.Equal(ErrInvalidEventType, RunWith(context.Background(), g, h, nil, handler))
+	assert.Equal(ErrInvalidListener, RunWith(context.Background(), g, h, reflect.TypeOf(0), nil))
+	assert.Equal(ErrInvalidEventType, RunWith(context.Background(), g, h, reflect.TypeOf((*error)(nil)).Elem(), handler))
+	assert.NoError(g.Wait())
+}
+
+func TestRunWith(t *testing.T) {
+	t.Run("Cancel", testRunWithCancel)
+	t.Run("Error", testRunWithError)
+	t.Run("Invalid", testRunWithInvalid)
+}
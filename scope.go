@@ -0,0 +1,124 @@
+package hub
+
+import (
+	"context"
+	"sync"
+)
+
+// Scope is a Subscriber whose subscriptions are tracked together, so that they can all be cancelled
+// with a single call to Close.  Events are still published through the hub that created the scope.
+// This is useful for per-request or per-plugin lifecycles.
+type Scope interface {
+	Subscriber
+
+	// Close cancels every subscription made through this scope that is still active.  After Close,
+	// Subscribe returns ErrClosed.  This method is idempotent, and it does not affect the parent hub.
+	Close()
+}
+
+type scope struct {
+	hub *hub
+
+	lock   sync.Mutex
+	closed bool
+	subs   map[*Subscription]bool
+
+	// done is closed by Close to release the goroutine started by ScopeContext.  it is nil otherwise.
+	done chan struct{}
+}
+
+func (h *hub) Scope() Scope {
+	return &scope{
+		hub:  h,
+		subs: make(map[*Subscription]bool),
+	}
+}
+
+func (h *hub) ScopeContext(ctx context.Context) Scope {
+	s := &scope{
+		hub:  h,
+		subs: make(map[*Subscription]bool),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+
+	return s
+}
+
+// track is a SubscribeOption that causes a subscription to remove itself from this scope
+// when it is cancelled by any means
+func (s *scope) track(sub *Subscription) {
+	sub.afterCancel = append(sub.afterCancel, func() {
+		s.lock.Lock()
+		delete(s.subs, sub)
+		s.lock.Unlock()
+	})
+}
+
+func (s *scope) Subscribe(l interface{}, afterCancel ...func()) (Cancel, error) {
+	s.lock.Lock()
+	closed := s.closed
+	s.lock.Unlock()
+
+	if closed {
+		return nil, ErrClosed
+	}
+
+	// the lock is not held while subscribing, since sticky events are delivered before SubscribeWith returns
+	// and a listener that cancels itself would take the lock in its afterCancel
+	var added *Subscription
+	sub, err := s.hub.SubscribeWith(l, WithAfterCancel(afterCancel...), func(sub *Subscription) {
+		added = sub
+		s.track(sub)
+	})
+
+	if err != nil {
+		return nil, err
+	} else if sub != added {
+		// WithDedup returned a subscription made by someone else, which this scope must not cancel
+		return sub.cancelFunc(), nil
+	}
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		sub.Cancel()
+		return nil, ErrClosed
+	}
+
+	if sub.Active() {
+		// a subscription cancelled during its sticky delivery has already run track's afterCancel
+		s.subs[sub] = true
+	}
+
+	s.lock.Unlock()
+	return sub.cancelFunc(), nil
+}
+
+func (s *scope) Close() {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+
+	s.closed = true
+	if s.done != nil {
+		close(s.done)
+	}
+
+	subs := s.subs
+	s.subs = make(map[*Subscription]bool)
+	s.lock.Unlock()
+
+	for sub := range subs {
+		sub.Cancel()
+	}
+}
@@ -0,0 +1,153 @@
+package hub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		received []string
+		after    int
+	)
+
+	Must(h.Subscribe(func(e string) { received = append(received, "hub:"+e) }))
+
+	s := h.Scope()
+	require.NotNil(s)
+
+	_, err := s.Subscribe(func(e string) { received = append(received, "scope1:"+e) }, func() { after++ })
+	require.NoError(err)
+
+	cancel2, err := s.Subscribe(func(e string) { received = append(received, "scope2:"+e) })
+	require.NoError(err)
+
+	cancel, err := s.Subscribe(func(string, string) {})
+	assert.Equal(ErrInvalidFunction, err)
+	assert.Nil(cancel)
+
+	h.Publish("a")
+	cancel2()
+	h.Publish("b")
+	s.Close()
+	h.Publish("c")
+
+	assert.Equal([]string{"hub:a", "scope1:a", "scope2:a", "hub:b", "scope1:b", "hub:c"}, received)
+	assert.Equal(1, after)
+
+	// idempotent
+	s.Close()
+	assert.Equal(1, after)
+
+	cancel, err = s.Subscribe(func(string) {})
+	assert.Equal(ErrClosed, err)
+	assert.Nil(cancel)
+}
+
+func TestScopeSticky(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h        = New()
+		received []int
+	)
+
+	h.PublishSticky(1)
+	s := h.Scope()
+
+	// a listener that cancels itself during its sticky delivery must not deadlock on the scope
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := s.Subscribe(func(e int, c Cancel) {
+			received = append(received, e)
+			c()
+		})
+
+		assert.NoError(err)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail("subscribing through a scope deadlocked")
+	}
+
+	assert.Equal([]int{1}, received)
+	h.Publish(2)
+	assert.Equal([]int{1}, received)
+	s.Close()
+}
+
+func TestScopeDedup(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New(WithDedup())
+		c = make(chan int, 10)
+	)
+
+	_, err := h.Subscribe(c)
+	require.NoError(err)
+
+	// the scope gets the existing subscription, but closing the scope leaves it to its owner
+	s := h.Scope()
+	_, err = s.Subscribe(c)
+	require.NoError(err)
+
+	s.Close()
+	h.Publish(1)
+	assert.Len(c, 1)
+}
+
+func TestScopeContext(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h           = New()
+		received    []string
+		ctx, cancel = context.WithCancel(context.Background())
+		cancelled   = make(chan struct{}, 2)
+	)
+
+	s := h.ScopeContext(ctx)
+	require.NotNil(s)
+
+	_, err := s.Subscribe(func(e string) { received = append(received, "scope1:"+e) }, func() { cancelled <- struct{}{} })
+	require.NoError(err)
+
+	_, err = s.Subscribe(func(e string) { received = append(received, "scope2:"+e) }, func() { cancelled <- struct{}{} })
+	require.NoError(err)
+
+	h.Publish("a")
+	cancel()
+	<-cancelled
+	<-cancelled
+	h.Publish("b")
+
+	assert.Equal([]string{"scope1:a", "scope2:a"}, received)
+
+	_, err = s.Subscribe(func(string) {})
+	assert.Equal(ErrClosed, err)
+
+	// closing a scope before its context is done releases the goroutine, and is idempotent
+	s = h.ScopeContext(context.Background())
+	_, err = s.Subscribe(func(e string) { received = append(received, "scope3:"+e) })
+	require.NoError(err)
+
+	s.Close()
+	assert.NotPanics(s.Close)
+	h.Publish("c")
+	assert.Equal([]string{"scope1:a", "scope2:a"}, received)
+}
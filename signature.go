@@ -0,0 +1,168 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+)
+
+// param identifies the value passed for one input parameter of a listener function or method
+type param int
+
+const (
+	// paramEvent is the event itself
+	paramEvent param = iota
+
+	// paramMeta is the Meta that accompanied the event
+	paramMeta
+
+	// paramCancel is the Cancel for the listener's own subscription
+	paramCancel
+
+	// paramType is the reflect.Type of the event
+	paramType
+
+	// paramContext is the context.Context that accompanied the event
+	paramContext
+
+	// paramRemaining is the number of events that follow this one in a PublishBatch
+	paramRemaining
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	cancelType  = reflect.TypeOf(Cancel(nil))
+	typeType    = reflect.TypeOf((*reflect.Type)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// signature describes the inputs of a listener function or method, excluding any receiver
+type signature struct {
+	eventType reflect.Type
+	params    []param
+
+	// returnsError indicates that the listener returns an error.  see SubscribeErr.
+	returnsError bool
+}
+
+// parseSignature examines the inputs of a function type starting at the given offset.  The offset
+// is 1 for method functions, so that the receiver is skipped.  The recognized shapes are:
+//
+//     func(E)
+//     func(E, Meta)
+//     func(E, Cancel)
+//     func(E, context.Context)
+//     func(E, Remaining)
+//     func(reflect.Type, E)
+//     func(context.Context, E)
+//
+// where E is the event type.  Outputs are never allowed.  Whether E itself is an acceptable event
+// type is left to the hub, since interfaces may be registered via RegisterImplementation.
+func parseSignature(ft reflect.Type, offset int) (signature, error) {
+	if ft.NumOut() != 0 {
+		return signature{}, ErrInvalidFunction
+	}
+
+	var sig signature
+	switch ft.NumIn() - offset {
+	case 1:
+		sig = signature{eventType: ft.In(offset), params: []param{paramEvent}}
+
+	case 2:
+		switch ft.In(offset) {
+		case typeType:
+			sig = signature{eventType: ft.In(offset + 1), params: []param{paramType, paramEvent}}
+			return sig, nil
+
+		case contextType:
+			sig = signature{eventType: ft.In(offset + 1), params: []param{paramContext, paramEvent}}
+			return sig, nil
+		}
+
+		switch ft.In(offset + 1) {
+		case metaType:
+			sig = signature{eventType: ft.In(offset), params: []param{paramEvent, paramMeta}}
+
+		case cancelType:
+			sig = signature{eventType: ft.In(offset), params: []param{paramEvent, paramCancel}}
+
+		case contextType:
+			sig = signature{eventType: ft.In(offset), params: []param{paramEvent, paramContext}}
+
+		case remainingType:
+			sig = signature{eventType: ft.In(offset), params: []param{paramEvent, paramRemaining}}
+
+		default:
+			return signature{}, ErrInvalidFunction
+		}
+
+	default:
+		return signature{}, ErrInvalidFunction
+	}
+
+	return sig, nil
+}
+
+// has tests if a listener with this signature receives the given parameter
+func (sig signature) has(p param) bool {
+	for _, candidate := range sig.params {
+		if candidate == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxArgs is the largest number of arguments, including a method receiver, that any listener takes
+const maxArgs = 3
+
+// args builds the argument list for invoking a listener with this signature, appending it to the given
+// slice.  Callers pass a slice of a fixed-size array, so that building arguments does not allocate.  Any
+// leading values, such as a method receiver, are placed before the listener's own parameters.
+func (sig signature) args(args []reflect.Value, m message, leading ...reflect.Value) []reflect.Value {
+	args = append(args, leading...)
+	for _, p := range sig.params {
+		switch p {
+		case paramEvent:
+			args = append(args, m.value)
+
+		case paramMeta:
+			args = append(args, reflect.ValueOf(m.meta))
+
+		case paramCancel:
+			args = append(args, reflect.ValueOf(m.sub.cancel))
+
+		case paramType:
+			args = append(args, reflect.ValueOf(m.value.Type()))
+
+		case paramContext:
+			args = append(args, reflect.ValueOf(m.context()))
+
+		case paramRemaining:
+			args = append(args, reflect.ValueOf(Remaining(m.remaining)))
+		}
+	}
+
+	return args
+}
+
+// returned handles the outputs of a listener with this signature.  an error returned by the listener fails
+// the delivery.  PublishGroup returns the error, and otherwise it is passed to the hub's error handler or,
+// if there is none, logged so that it is not silently lost.
+func (sig signature) returned(m message, outputs []reflect.Value) {
+	if !sig.returnsError || outputs[0].IsNil() {
+		return
+	}
+
+	err := outputs[0].Interface().(error)
+	m.fail(Errored, err)
+	if m.group != nil {
+		return
+	}
+
+	if h := m.sub.hub; h.onError != nil {
+		h.onError(err)
+	} else {
+		h.logf("listener for %s returned an error: %v", sig.eventType, err)
+	}
+}
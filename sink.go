@@ -3,6 +3,9 @@ package hub
 import (
 	"errors"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -15,7 +18,9 @@ var (
 	//    h.Subscribe(func(*bytes.Buffer) {})
 	ErrInvalidListener = errors.New("A listener must be a function, channel, or have exactly (1) method")
 
-	// ErrInvalidFunction indicates that a function or method did not have the correct signature.  For example:
+	// ErrInvalidFunction indicates that a function or method did not have the correct signature.  A listener
+	// function or method takes the event as its sole input, optionally followed by a Meta, and has no outputs.
+	// For example:
 	//
 	//    // more than one input parameter
 	//    h.Subscribe(func(string, int) {})
@@ -30,45 +35,128 @@ var (
 	//    h.Subscribe(c)
 	ErrInvalidChannel = errors.New("A listener channel must be bidirectional or send-only")
 
-	// ErrInvalidEventType indicates an attempt to subscribe to an interface type as an event.  For example,
-	// these calls to Subscribe result in ErrInvalidEventType:
+	// ErrInvalidEventType indicates an attempt to subscribe to an interface type as an event, unless that
+	// interface was registered with RegisterImplementation.  For example, these calls to Subscribe result
+	// in ErrInvalidEventType:
 	//
 	//    h.Subscribe(func(io.Reader) {})
 	//    c := make(chan error)
 	//    h.Subscribe(c)
 	ErrInvalidEventType = errors.New("Event types cannot be interfaces")
+
+	// ErrNoSuchMethod indicates that SubscribeMethod was passed the name of a method that does not
+	// exist in the receiver's method set.  Only exported methods can be used as listeners.
+	ErrNoSuchMethod = errors.New("The receiver has no exported method with that name")
 )
 
+// anyType is the key under which catch-all sinks are stored.  since interfaces are never
+// valid event types, this key cannot collide with a normal subscription.
+var anyType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// Sink is a custom listener strategy.  Implementations can batch, forward remotely, or otherwise
+// handle events while still using a hub's routing and cancellation.  Send is invoked synchronously
+// from Publish, and may be invoked concurrently.
+type Sink interface {
+	Send(interface{})
+}
+
+// sinkCustom adapts a Sink to the internal sink interface
+type sinkCustom struct {
+	s Sink
+}
+
+func (sc *sinkCustom) send(m message) {
+	sc.s.Send(m.value.Interface())
+}
+
 // sink is an internal strategy interface for sending event objects to destinations
 type sink interface {
-	send(reflect.Value)
+	send(message)
 }
 
 type sinkFunc struct {
-	f reflect.Value
+	f   reflect.Value
+	sig signature
 }
 
-func (sf *sinkFunc) send(v reflect.Value) {
-	sf.f.Call([]reflect.Value{v})
+func (sf *sinkFunc) send(m message) {
+	var fixed [maxArgs]reflect.Value
+	sf.sig.returned(m, sf.f.Call(sf.sig.args(fixed[:0], m)))
 }
 
 type sinkChan struct {
 	c reflect.Value
 }
 
-func (sc *sinkChan) send(v reflect.Value) {
-	sc.c.Send(v)
+func (sc *sinkChan) send(m message) {
+	a := m.sub.ack
+	if a != nil {
+		a.sendLock.Lock()
+		defer a.sendLock.Unlock()
+	}
+
+	sent, closed := sc.sendChecked(m)
+	if closed {
+		atomic.AddUint64(&m.sub.hub.counters.dropped, 1)
+		m.fail(Dropped, ErrClosedChannel)
+		if m.sub.hub.closedChannelPolicy == ClosedChannelRemove {
+			m.sub.Cancel()
+		}
+
+		return
+	} else if !sent {
+		atomic.AddUint64(&m.sub.hub.counters.dropped, 1)
+		m.fail(Dropped, nil)
+		return
+	}
+
+	if a != nil {
+		m.expectAck(a, a.next())
+	}
+}
+
+// trySend sends a message's event to the channel, returning false if the hub's channel send timeout elapsed first
+func (sc *sinkChan) trySend(m message) bool {
+	timeout := m.sub.hub.channelSendTimeout
+	if timeout <= 0 {
+		sc.c.Send(m.value)
+		return true
+	}
+
+	// avoid creating a timer when the channel is ready
+	if sc.c.TrySend(m.value) {
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: sc.c, Send: m.value},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)},
+	})
+
+	return chosen == 0
+}
+
+// sinkAll is a catch-all sink that receives events of any type
+type sinkAll struct {
+	f func(interface{})
+}
+
+func (sa *sinkAll) send(m message) {
+	sa.f(m.event())
 }
 
 type sinkMethod struct {
-	r reflect.Value // receiver
-	m reflect.Value // method function itself
+	r   reflect.Value // receiver
+	m   reflect.Value // method function itself
+	sig signature
 }
 
-func (sm *sinkMethod) send(v reflect.Value) {
-	sm.m.Call(
-		[]reflect.Value{sm.r, v},
-	)
+func (sm *sinkMethod) send(m message) {
+	var fixed [maxArgs]reflect.Value
+	sm.m.Call(sm.sig.args(fixed[:0], m, sm.r))
 }
 
 // newSink reflects on t and determines the event type and a sink strategy for sending the event
@@ -78,48 +166,109 @@ func newSink(t interface{}) (reflect.Type, sink, error) {
 		return nil, nil, ErrInvalidListener
 	}
 
-	var (
-		eventType reflect.Type
-		s         sink
-	)
-
 	switch {
 	case listenerType.Kind() == reflect.Func:
-		if listenerType.NumIn() != 1 || listenerType.NumOut() != 0 {
-			return nil, nil, ErrInvalidFunction
+		sig, err := parseSignature(listenerType, 0)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		eventType = listenerType.In(0)
-		s = &sinkFunc{f: reflect.ValueOf(t)}
+		return sig.eventType, &sinkFunc{f: reflect.ValueOf(t), sig: sig}, nil
 
 	case listenerType.Kind() == reflect.Chan:
 		if listenerType.ChanDir() == reflect.RecvDir {
 			return nil, nil, ErrInvalidChannel
 		}
 
-		eventType = listenerType.Elem()
-		s = &sinkChan{c: reflect.ValueOf(t)}
+		return listenerType.Elem(), &sinkChan{c: reflect.ValueOf(t)}, nil
 
 	case listenerType.NumMethod() == 1:
-		m := listenerType.Method(0)
+		return newCachedMethodSink(reflect.ValueOf(t), listenerType)
 
-		// for a method, we include the receiver, which is the first parameter
-		// that means the number of in parameters should be (2), with the event
-		// object being the second parameter
-		if m.Func.Type().NumIn() != 2 || m.Func.Type().NumOut() != 0 {
-			return nil, nil, ErrInvalidFunction
-		}
+	default:
+		return nil, nil, ErrInvalidListener
+	}
+}
 
-		eventType = m.Func.Type().In(1)
-		s = &sinkMethod{r: reflect.ValueOf(t), m: m.Func}
+// newErrSink creates the sink for a listener passed to SubscribeErr.  the listener must be a function that
+// returns a single error, and its inputs may take any of the shapes accepted by parseSignature.
+func newErrSink(l interface{}) (reflect.Type, sink, error) {
+	ft := reflect.TypeOf(l)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil, nil, ErrInvalidListener
+	}
 
-	default:
+	if ft.NumOut() != 1 || ft.Out(0) != errorType {
+		return nil, nil, ErrInvalidFunction
+	}
+
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+
+	sig, err := parseSignature(reflect.FuncOf(in, nil, ft.IsVariadic()), 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig.returnsError = true
+	return sig.eventType, &sinkFunc{f: reflect.ValueOf(l), sig: sig}, nil
+}
+
+// newMethodSink validates a method on a receiver and creates the sink that invokes it
+func newMethodSink(r reflect.Value, m reflect.Method) (reflect.Type, sink, error) {
+	// for a method, we include the receiver, which is the first parameter
+	// so the event object begins at the second parameter
+	sig, err := parseSignature(m.Func.Type(), 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sig.eventType, &sinkMethod{r: r, m: m.Func, sig: sig}, nil
+}
+
+// methodListener is the cached result of validating the sole method of a listener type
+type methodListener struct {
+	m   reflect.Method
+	sig signature
+	err error
+}
+
+// methodListeners caches a methodListener for each type with exactly one method that has been
+// passed to newSink, so that subscribing many instances of the same type validates it only once
+var methodListeners sync.Map
+
+// newCachedMethodSink creates the sink for a receiver whose type has exactly one method, consulting
+// and populating methodListeners
+func newCachedMethodSink(r reflect.Value, listenerType reflect.Type) (reflect.Type, sink, error) {
+	cached, ok := methodListeners.Load(listenerType)
+	if !ok {
+		ml := methodListener{m: listenerType.Method(0)}
+		ml.sig, ml.err = parseSignature(ml.m.Func.Type(), 1)
+		cached, _ = methodListeners.LoadOrStore(listenerType, ml)
+	}
+
+	ml := cached.(methodListener)
+	if ml.err != nil {
+		return nil, nil, ml.err
+	}
+
+	return ml.sig.eventType, &sinkMethod{r: r, m: ml.m.Func, sig: ml.sig}, nil
+}
+
+// newNamedMethodSink looks up a method by name on a receiver and creates the sink that invokes it.
+// Unlike newSink, the receiver may have any number of methods.
+func newNamedMethodSink(receiver interface{}, methodName string) (reflect.Type, sink, error) {
+	receiverType := reflect.TypeOf(receiver)
+	if receiverType == nil {
 		return nil, nil, ErrInvalidListener
 	}
 
-	if eventType.Kind() == reflect.Interface {
-		return nil, nil, ErrInvalidEventType
+	m, ok := receiverType.MethodByName(methodName)
+	if !ok {
+		return nil, nil, ErrNoSuchMethod
 	}
 
-	return eventType, s, nil
+	return newMethodSink(reflect.ValueOf(receiver), m)
 }
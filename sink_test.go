@@ -0,0 +1,76 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cachedListener struct {
+	received *[]string
+}
+
+func (cl cachedListener) OnString(s string) {
+	*cl.received = append(*cl.received, s)
+}
+
+type invalidCachedListener struct{}
+
+func (invalidCachedListener) Invalid(a, b int) {}
+
+func TestMethodListenerCache(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		first, second []string
+		h             = New()
+	)
+
+	Must(h.Subscribe(cachedListener{received: &first}))
+	_, cached := methodListeners.Load(reflect.TypeOf(cachedListener{}))
+	assert.True(cached)
+
+	// a second instance of the same type uses the cached method, but its own receiver
+	Must(h.Subscribe(cachedListener{received: &second}))
+	h.Publish("event")
+	assert.Equal([]string{"event"}, first)
+	assert.Equal([]string{"event"}, second)
+
+	// failures are cached as well
+	for i := 0; i < 2; i++ {
+		_, err := h.Subscribe(invalidCachedListener{})
+		assert.Equal(ErrInvalidFunction, err)
+	}
+}
+
+func BenchmarkSubscribeMethod(b *testing.B) {
+	var received []string
+	h := New()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cancel, _ := h.Subscribe(cachedListener{received: &received})
+		cancel()
+	}
+}
+
+// nopListener is a method listener that does nothing, so that it never allocates
+type nopListener struct{}
+
+func (nopListener) OnInt(int) {}
+
+func TestSinkArgsAllocations(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		m   = message{value: reflect.ValueOf(1)}
+		sig = signature{eventType: reflect.TypeOf(0), params: []param{paramEvent}}
+		sf  = &sinkFunc{f: reflect.ValueOf(func(int) {}), sig: sig}
+		sm  = &sinkMethod{r: reflect.ValueOf(nopListener{}), m: reflect.ValueOf(nopListener.OnInt), sig: sig}
+	)
+
+	// building arguments must not allocate for the common shapes
+	assert.Zero(testing.AllocsPerRun(100, func() { sf.send(m) }))
+	assert.Zero(testing.AllocsPerRun(100, func() { sm.send(m) }))
+}
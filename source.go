@@ -0,0 +1,43 @@
+package hub
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErrInvalidSource indicates that PublishFrom was passed something other than a channel that can be received from
+var ErrInvalidSource = errors.New("A source must be a bidirectional or receive-only channel")
+
+func (h *hub) PublishFrom(ch interface{}) (Cancel, error) {
+	source := reflect.ValueOf(ch)
+	if source.Kind() != reflect.Chan || source.Type().ChanDir() == reflect.SendDir || source.IsNil() {
+		return nil, ErrInvalidSource
+	}
+
+	var (
+		stop = make(chan struct{})
+		once sync.Once
+	)
+
+	go func() {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+			{Dir: reflect.SelectRecv, Chan: source},
+		}
+
+		for {
+			chosen, v, ok := reflect.Select(cases)
+			if chosen == 0 || !ok {
+				return
+			}
+
+			// routing uses each element's dynamic type, which matters for channels of interfaces
+			h.Publish(v.Interface())
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(stop) })
+	}, nil
+}
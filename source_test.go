@@ -0,0 +1,91 @@
+package hub
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPublishFromClose(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		ints    = make(chan int, 3)
+		strings = make(chan string, 3)
+		h       = New()
+	)
+
+	Must(h.Subscribe(ints))
+	Must(h.Subscribe(strings))
+
+	// each element is routed by its dynamic type
+	source := make(chan interface{}, 3)
+	source <- 1
+	source <- "two"
+	source <- 3
+	close(source)
+
+	cancel, err := h.PublishFrom(source)
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	assert.Equal(1, <-ints)
+	assert.Equal("two", <-strings)
+	assert.Equal(3, <-ints)
+
+	cancel()
+	cancel()
+}
+
+func testPublishFromCancel(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received = make(chan string)
+		h        = New()
+	)
+
+	Must(h.Subscribe(received))
+
+	source := make(chan string)
+	cancel, err := h.PublishFrom((<-chan string)(source))
+	require.NoError(err)
+
+	source <- "first"
+	assert.Equal("first", <-received)
+
+	cancel()
+
+	// once canceled, nothing receives from the source
+	select {
+	case source <- "second":
+		assert.Fail("the source should no longer be read")
+	default:
+	}
+}
+
+func testPublishFromInvalid(t *testing.T) {
+	h := New()
+	for _, invalid := range []interface{}{
+		nil,
+		123,
+		make(chan<- int),
+		(chan int)(nil),
+	} {
+		t.Run(fmt.Sprintf("%T", invalid), func(t *testing.T) {
+			cancel, err := h.PublishFrom(invalid)
+			assert.Equal(t, ErrInvalidSource, err)
+			assert.Nil(t, cancel)
+		})
+	}
+}
+
+func TestPublishFrom(t *testing.T) {
+	t.Run("Close", testPublishFromClose)
+	t.Run("Cancel", testPublishFromCancel)
+	t.Run("Invalid", testPublishFromInvalid)
+}
@@ -0,0 +1,103 @@
+package hub
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Stats is a point-in-time summary of a hub, suitable for health and metrics endpoints
+type Stats struct {
+	// TotalSubscriptions is the number of active subscriptions, including catch-all subscriptions
+	TotalSubscriptions int
+
+	// EventTypeCount is the number of distinct event types with at least one subscription.
+	// Catch-all subscriptions are not counted as an event type.
+	EventTypeCount int
+
+	// PublishCount is the number of events published over the hub's lifetime.  Events that were
+	// accepted by PublishAsync are counted, while events it dropped are not.
+	PublishCount uint64
+
+	// DroppedCount is the number of events or deliveries that were dropped rather than delivered,
+	// such as events rejected by a full asynchronous queue or channel sends that exceeded WithChannelSendTimeout
+	DroppedCount uint64
+
+	// TotalSubscribed is the number of subscriptions added over the hub's lifetime, and TotalCancelled is the
+	// number of those that have been cancelled by any means.  Each subscription is counted at most once in each,
+	// no matter how many times it is cancelled.  Their difference is the number of active subscriptions, and
+	// their rates of change indicate subscription churn.
+	TotalSubscribed uint64
+	TotalCancelled  uint64
+}
+
+// counters holds the monotonic counts reported by Stats, along with other atomically updated counts.  it must be the first field of
+// the hub struct, so that its 64-bit values are aligned for atomic access on 32-bit platforms.
+type counters struct {
+	published  uint64
+	dropped    uint64
+	subscribed uint64
+	cancelled  uint64
+
+	// ids is the last id assigned to a subscription
+	ids uint64
+}
+
+func (h *hub) Stats() Stats {
+	s := Stats{
+		PublishCount: atomic.LoadUint64(&h.counters.published),
+		DroppedCount: atomic.LoadUint64(&h.counters.dropped),
+
+		TotalSubscribed: atomic.LoadUint64(&h.counters.subscribed),
+		TotalCancelled:  atomic.LoadUint64(&h.counters.cancelled),
+	}
+
+	h.subscriptions.each(func(route interface{}, subs []*Subscription) {
+		s.TotalSubscriptions += len(subs)
+		if eventType, ok := route.(reflect.Type); ok && eventType != anyType {
+			s.EventTypeCount++
+		}
+	})
+
+	return s
+}
+
+// countPublish records the publication of an event of the given type, which may be nil
+func (h *hub) countPublish(eventType reflect.Type) {
+	atomic.AddUint64(&h.counters.published, 1)
+	if eventType == nil {
+		return
+	}
+
+	count, ok := h.publishCounts.Load(eventType)
+	if !ok {
+		count, _ = h.publishCounts.LoadOrStore(eventType, new(uint64))
+	}
+
+	atomic.AddUint64(count.(*uint64), 1)
+}
+
+// countSubscribe records a subscription that has just been added
+func (h *hub) countSubscribe(sub *Subscription) {
+	atomic.AddUint64(&h.counters.subscribed, 1)
+	if h.observer.OnSubscribe != nil {
+		h.observer.OnSubscribe(sub.eventType)
+	}
+}
+
+// countCancel records a subscription that has just been cancelled.  it is called once per subscription.
+func (h *hub) countCancel(sub *Subscription) {
+	atomic.AddUint64(&h.counters.cancelled, 1)
+	if h.observer.OnCancel != nil {
+		h.observer.OnCancel(sub.eventType)
+	}
+}
+
+func (h *hub) PublishCounts() map[reflect.Type]uint64 {
+	counts := make(map[reflect.Type]uint64)
+	h.publishCounts.Range(func(k, v interface{}) bool {
+		counts[k.(reflect.Type)] = atomic.LoadUint64(v.(*uint64))
+		return true
+	})
+
+	return counts
+}
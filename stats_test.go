@@ -0,0 +1,114 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		gate = make(chan struct{})
+		done = make(chan struct{})
+		h    = New(WithGlobalQueue(1), WithDropWhenFull())
+	)
+
+	assert.Equal(Stats{}, h.Stats())
+
+	Must(h.Subscribe(func(int) {}))
+	Must(h.Subscribe(func(int) {}))
+	cancel := Must(h.Subscribe(func(string) {
+		<-gate
+		close(done)
+	}))
+
+	Must(h.SubscribeAll(func(interface{}) {}))
+
+	h.Publish(1)
+	h.Publish(2.0)
+	assert.Equal(
+		Stats{TotalSubscriptions: 4, EventTypeCount: 2, PublishCount: 2, TotalSubscribed: 4},
+		h.Stats(),
+	)
+
+	assert.True(h.PublishAsync("blocked"))
+	assert.False(h.PublishAsync("dropped"))
+	close(gate)
+	<-done
+
+	cancel()
+	assert.Equal(
+		Stats{TotalSubscriptions: 3, EventTypeCount: 1, PublishCount: 3, DroppedCount: 1, TotalSubscribed: 4, TotalCancelled: 1},
+		h.Stats(),
+	)
+}
+
+func TestSubscriptionChurn(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		subscribed []reflect.Type
+		cancelled  []reflect.Type
+
+		h = New(WithDedup(), WithObserver(Observer{
+			OnSubscribe: func(eventType reflect.Type) { subscribed = append(subscribed, eventType) },
+			OnCancel:    func(eventType reflect.Type) { cancelled = append(cancelled, eventType) },
+		}))
+
+		l = func(int) {}
+	)
+
+	cancel := Must(h.Subscribe(l))
+	Must(h.Subscribe(l))
+	Must(h.Subscribe(func(string) {}))
+
+	// repeated cancels and duplicates are only counted once
+	cancel()
+	cancel()
+
+	stats := h.Stats()
+	assert.Equal(uint64(2), stats.TotalSubscribed)
+	assert.Equal(uint64(1), stats.TotalCancelled)
+
+	h.Close()
+	stats = h.Stats()
+	assert.Equal(uint64(2), stats.TotalSubscribed)
+	assert.Equal(uint64(2), stats.TotalCancelled)
+
+	assert.Equal([]reflect.Type{reflect.TypeOf(0), reflect.TypeOf("")}, subscribed)
+	assert.Equal([]reflect.Type{reflect.TypeOf(0), reflect.TypeOf("")}, cancelled)
+}
+
+func TestPublishCounts(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	assert.Empty(h.PublishCounts())
+
+	Must(h.Subscribe(func(int) {}))
+	h.Publish(1)
+	h.Publish(2)
+	h.Publish("unhandled")
+	h.Publish(nil)
+	h.PublishTyped(reflect.TypeOf(0), 3)
+
+	counts := h.PublishCounts()
+	assert.Equal(
+		map[reflect.Type]uint64{
+			reflect.TypeOf(0):  3,
+			reflect.TypeOf(""): 1,
+		},
+		counts,
+	)
+
+	// the returned map is a snapshot
+	h.Publish(4)
+	assert.Equal(uint64(3), counts[reflect.TypeOf(0)])
+	assert.Equal(uint64(4), h.PublishCounts()[reflect.TypeOf(0)])
+	assert.Equal(uint64(6), h.Stats().PublishCount)
+}
@@ -0,0 +1,35 @@
+package hub
+
+import "reflect"
+
+// Strategy describes how Publish delivers an event
+type Strategy int
+
+const (
+	// Sync delivers each event on the publishing goroutine before Publish returns.  This is the default.
+	Sync Strategy = iota
+
+	// Async enqueues each event for the hub's workers, exactly as PublishAsync does
+	Async
+)
+
+// String returns a human-readable name for this strategy
+func (s Strategy) String() string {
+	switch s {
+	case Sync:
+		return "Sync"
+	case Async:
+		return "Async"
+	default:
+		return "Strategy(invalid)"
+	}
+}
+
+// strategyFor returns the strategy that Publish uses for the given event type
+func (h *hub) strategyFor(eventType reflect.Type) Strategy {
+	if s, ok := h.typeStrategies[eventType]; ok {
+		return s
+	}
+
+	return h.strategy
+}
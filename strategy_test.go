@@ -0,0 +1,83 @@
+package hub
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStrategyString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("Sync", Sync.String())
+	assert.Equal("Async", Async.String())
+	assert.Equal("Strategy(invalid)", Strategy(-1).String())
+}
+
+func testStrategyTypeOverride(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		caller = make(chan bool, 10)
+		h      = New(WithTypeStrategy(reflect.TypeOf(""), Async), WithTypeStrategy(nil, Async))
+	)
+
+	defer h.Close()
+
+	// each listener reports whether it ran while the publisher's flag was set
+	var publishing bool
+	Must(h.Subscribe(func(int) { caller <- publishing }))
+	Must(h.Subscribe(func(string) {
+		time.Sleep(10 * time.Millisecond)
+		caller <- false
+	}))
+
+	publishing = true
+	h.Publish(1)
+	publishing = false
+	assert.True(<-caller, "int events should be delivered synchronously")
+
+	h.Publish("async")
+	select {
+	case <-caller:
+		assert.Fail("string events should be delivered asynchronously")
+	default:
+	}
+
+	h.Flush()
+	assert.False(<-caller)
+}
+
+func testStrategyPrecedence(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		received = make(chan interface{}, 10)
+		h        = New(
+			WithTypeStrategy(reflect.TypeOf(0), Sync),
+			WithStrategy(Async),
+		)
+	)
+
+	defer h.Close()
+	Must(h.SubscribeAll(func(e interface{}) { received <- e }))
+
+	// the per-type strategy wins regardless of option order
+	h.Publish(1)
+	assert.Len(received, 1)
+
+	h.Publish("async")
+	h.Flush()
+	assert.Len(received, 2)
+
+	// other publish methods ignore the strategy
+	assert.True(h.PublishOK("sync"))
+	assert.Len(received, 3)
+}
+
+func TestStrategy(t *testing.T) {
+	t.Run("String", testStrategyString)
+	t.Run("TypeOverride", testStrategyTypeOverride)
+	t.Run("Precedence", testStrategyPrecedence)
+}
@@ -0,0 +1,261 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// SubscribeOption is a configurable option for an individual subscription
+type SubscribeOption func(*Subscription)
+
+// WithAfterCancel adds closures that are invoked during cancellation after the listener has been
+// deregistered.  This is the option form of the afterCancel parameter to Subscribe.
+func WithAfterCancel(f ...func()) SubscribeOption {
+	return func(s *Subscription) {
+		s.afterCancel = append(s.afterCancel, f...)
+	}
+}
+
+// WithLabel attaches a caller-supplied label to a subscription.  Labels identify subscriptions in
+// audit entries and other diagnostics, and have no effect on delivery.
+func WithLabel(label string) SubscribeOption {
+	return func(s *Subscription) {
+		s.label = label
+	}
+}
+
+// WithCloseOnCancel closes a channel listener once its subscription is cancelled, including when
+// the hub is closed.  This option has no effect on other kinds of listeners.
+//
+// The same channel may be subscribed to a hub more than once with this option, e.g. by different modules that
+// share it.  Such subscriptions are reference counted, and the channel is closed only when the last of them is
+// cancelled.  Subscriptions of the channel made without this option are not counted.  Each hub counts only its own
+// subscriptions, so a channel shared between hubs is closed once the last subscription to any one of them ends.
+//
+// As with any afterCancel closure that closes a channel, there must be no concurrent publishes of the
+// channel's event type when the last subscription is cancelled.
+func WithCloseOnCancel() SubscribeOption {
+	return func(s *Subscription) {
+		if sc, ok := s.sink.(*sinkChan); ok {
+			s.closes = sc.c
+			s.afterCancel = append(s.afterCancel, func() { s.hub.channelRefs.release(sc.c) })
+		}
+	}
+}
+
+// WithInterceptor attaches a veto function to a subscription.  Before each event is delivered to the
+// subscription's listener, the interceptor is invoked with the event.  If it returns false, neither that
+// listener nor any listener after it in the same event type's bucket receives the event.  Listeners for other
+// buckets, such as catch-all listeners, are unaffected.  Interception is therefore order-sensitive, and
+// interceptors are typically attached to the earliest subscriptions for a type.  See WithDeliveryOrder.
+//
+// Interceptors are intended for synchronous publishing.  With PublishAsync, an interceptor still halts
+// delivery within its bucket, but it runs on a worker goroutine and cannot report anything to the publisher.
+func WithInterceptor(f func(interface{}) bool) SubscribeOption {
+	return func(s *Subscription) {
+		s.interceptor = f
+	}
+}
+
+// WithSkip causes a subscription to ignore the first n events it would otherwise receive.  Delivery
+// starts with the next event.  Skipped events still count as matches for PublishOK and unhandled event
+// detection.  Events published while the subscription is disabled via SetEnabled are not counted.
+// A value of n less than 1 skips nothing.
+func WithSkip(n int) SubscribeOption {
+	return func(s *Subscription) {
+		if n > 0 {
+			s.skip = uint64(n)
+		}
+	}
+}
+
+// WithLimit causes a subscription to cancel itself once its listener has received n events.  The limit
+// is enforced atomically, so concurrent publishes never deliver more than n events.  Cancellation happens
+// once the publish that delivered the nth event completes, including any afterCancel closures, and the
+// returned Cancel may still be used to end the subscription early.  A value of n less than 1 imposes no limit.
+//
+// WithLimit composes with WithSkip, and skipped events do not count toward the limit.  For example, WithSkip(2)
+// together with WithLimit(5) ignores two events, delivers the next five, and then cancels.  WithLimit(1) delivers
+// a single event.
+func WithLimit(n int) SubscribeOption {
+	return func(s *Subscription) {
+		if n > 0 {
+			s.limit = uint64(n)
+		}
+	}
+}
+
+// Subscription is a handle to a registered listener.  It is a richer alternative to Cancel, allowing
+// callers to query the state of a subscription in addition to cancelling it.  Subscription instances are
+// safe for concurrent use.
+type Subscription struct {
+	// id uniquely identifies this subscription within its hub.  removal compares ids rather than
+	// pointers, so that cancellation does not depend on the identity of any particular wrapper.
+	id uint64
+
+	// occurrences counts the events that have reached this subscription while enabled.  it is accessed
+	// atomically, and is kept adjacent to id so that it remains 64-bit aligned.
+	occurrences uint64
+
+	// skip is the number of events to ignore before delivery starts.  see WithSkip.
+	skip uint64
+
+	// limit, if nonzero, is the number of events delivered before this subscription cancels itself.  see WithLimit.
+	limit uint64
+
+	// ack tracks acknowledgements for a channel listener that uses WithAck.  it is nil otherwise.
+	ack *ackState
+
+	// closes is the channel that WithCloseOnCancel closes.  it is the zero Value otherwise.
+	closes reflect.Value
+
+	hub         *hub
+	eventType   reflect.Type
+	route       interface{}
+	sink        sink
+	key         interface{}
+	label       string
+	afterCancel []func()
+	once        sync.Once
+
+	// group is the name of the consumer group this subscription belongs to, if any.  see WithConsumerGroup.
+	group string
+
+	// stickyAll causes this subscription to receive every retained sticky event, rather than just the one
+	// for its type, when it is added.  see TeeWithReplay.
+	stickyAll bool
+
+	// rank positions this subscription within its bucket.  see WithSinkKindOrder.
+	rank int
+
+	// mailbox is the queue capacity for a subscription made with WithMailbox, and is zero otherwise.  drain
+	// causes the mailbox to deliver its remaining events on cancellation.  see WithDrainOnCancel.
+	mailbox int
+	drain   bool
+
+	// interceptor, if set, can veto delivery to this and later subscriptions in the same bucket
+	interceptor func(interface{}) bool
+
+	// disabled is nonzero while this subscription is paused via SetEnabled.  it is accessed atomically.
+	disabled uint32
+
+	// cancel is the Cancel closure for this subscription, created once so that it can be passed
+	// to listeners cheaply
+	cancel Cancel
+}
+
+// EventType returns the type of event this subscription receives
+func (s *Subscription) EventType() reflect.Type {
+	return s.eventType
+}
+
+// EventTypeName returns the fully qualified name of this subscription's event type, e.g. "github.com/me/app.UserCreated".
+// Unlike the String method of reflect.Type, which uses only the last element of the package path, this name is stable
+// and unambiguous across packages and process restarts, which suits admin tooling.  A pointer to a named type is
+// prefixed with "*".  Other unnamed types, such as builtin types and the empty interface of catch-all subscriptions,
+// are described exactly as by reflect.Type.
+func (s *Subscription) EventTypeName() string {
+	return typeName(s.eventType)
+}
+
+// Label returns the label supplied via WithLabel, or the empty string if no label was supplied
+func (s *Subscription) Label() string {
+	return s.label
+}
+
+// Active tests if this subscription is still registered with its hub.  This method consults the hub's current
+// set of subscriptions, so it reflects removals by any means and not just calls to Cancel.
+func (s *Subscription) Active() bool {
+	for _, candidate := range s.hub.subscriptions.sinks(s.route) {
+		if candidate.id == s.id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetEnabled pauses or resumes delivery to this subscription.  A disabled subscription remains registered,
+// so it is still reported by Active and Describe and still counts as a match for PublishOK and unhandled
+// event detection, but its listener is skipped during delivery.  Subscriptions are enabled when created.
+func (s *Subscription) SetEnabled(enabled bool) {
+	var disabled uint32
+	if !enabled {
+		disabled = 1
+	}
+
+	atomic.StoreUint32(&s.disabled, disabled)
+}
+
+// Enabled tests if this subscription currently receives events.  See SetEnabled.
+func (s *Subscription) Enabled() bool {
+	return atomic.LoadUint32(&s.disabled) == 0
+}
+
+// admit counts an event that has reached this subscription, returning whether it should be delivered and
+// whether it is the last event permitted by WithLimit.  the counter is atomic, so concurrent publishes each
+// observe a distinct occurrence, and exactly one of them is last.
+func (s *Subscription) admit() (admitted, last bool) {
+	if s.skip == 0 && s.limit == 0 {
+		return true, false
+	}
+
+	n := atomic.AddUint64(&s.occurrences, 1)
+	switch {
+	case n <= s.skip:
+		return false, false
+
+	case s.limit == 0:
+		return true, false
+
+	default:
+		end := s.skip + s.limit
+		return n <= end, n == end
+	}
+}
+
+// Ack acknowledges the oldest event received from this subscription's channel that has not yet been acknowledged.
+// See WithAck.  This method does nothing if the subscription does not use WithAck or if every event sent so far
+// has been acknowledged.
+func (s *Subscription) Ack() {
+	if s.ack != nil {
+		s.ack.ack()
+	}
+}
+
+// Cancel removes this subscription from its hub, then invokes any afterCancel closures.  This method is
+// idempotent.  It returns true only for the call that actually removed the subscription.
+func (s *Subscription) Cancel() (cancelled bool) {
+	s.once.Do(func() {
+		cancelled = s.hub.remove(s)
+		s.finish()
+	})
+
+	return
+}
+
+// release completes the cancellation of a subscription that a bulk operation has already
+// removed from its hub.  Subsequent calls to Cancel will do nothing and return false.
+func (s *Subscription) release() {
+	s.once.Do(s.finish)
+}
+
+// finish performs the work that follows removing this subscription from its hub
+func (s *Subscription) finish() {
+	if s.ack != nil {
+		// waiters in PublishAck must not wait on a subscription that will never receive anything
+		s.ack.cancel()
+	}
+
+	s.hub.countCancel(s)
+	s.hub.audit(AuditCancel, s)
+	for _, f := range s.afterCancel {
+		f()
+	}
+}
+
+// cancelFunc adapts this subscription to the Cancel type
+func (s *Subscription) cancelFunc() Cancel {
+	return s.cancel
+}
@@ -0,0 +1,342 @@
+package hub
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received    []int
+		afterCancel int
+		h           = New()
+	)
+
+	sub, err := h.SubscribeWith(
+		func(e int) { received = append(received, e) },
+		WithAfterCancel(func() { afterCancel++ }),
+	)
+
+	require.NoError(err)
+	require.NotNil(sub)
+
+	assert.Equal(reflect.TypeOf(0), sub.EventType())
+	assert.True(sub.Active())
+
+	h.Publish(1)
+	assert.True(sub.Cancel())
+	assert.False(sub.Active())
+	assert.Equal(1, afterCancel)
+
+	// idempotent
+	assert.False(sub.Cancel())
+	assert.Equal(1, afterCancel)
+
+	h.Publish(2)
+	assert.Equal([]int{1}, received)
+
+	sub, err = h.SubscribeWith(func(string, int) {})
+	assert.Equal(ErrInvalidFunction, err)
+	assert.Nil(sub)
+}
+
+func TestWithCloseOnCancel(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c = make(chan int, 1)
+		h = New()
+	)
+
+	sub, err := h.SubscribeWith(c, WithCloseOnCancel())
+	require.NoError(err)
+
+	h.Publish(1)
+	sub.Cancel()
+
+	assert.Equal(1, <-c)
+	_, ok := <-c
+	assert.False(ok)
+
+	// non-channel listeners are unaffected
+	sub, err = h.SubscribeWith(func(int) {}, WithCloseOnCancel())
+	require.NoError(err)
+	assert.NotPanics(func() { sub.Cancel() })
+}
+
+func TestWithCloseOnCancelShared(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		c     = make(chan int, 10)
+		h     = New(WithDedup())
+		other = New()
+	)
+
+	first, err := h.SubscribeWith(c, WithCloseOnCancel())
+	require.NoError(err)
+
+	// a send-only view of the channel is the same channel
+	var sendOnly chan<- int = c
+	second, err := h.SubscribeWith(sendOnly, WithCloseOnCancel())
+	require.NoError(err)
+
+	// a duplicate returned by WithDedup holds no extra reference
+	duplicate, err := h.SubscribeWith(sendOnly, WithCloseOnCancel())
+	require.NoError(err)
+	assert.True(second == duplicate)
+
+	// another hub keeps its own count, so its subscription does not keep the channel open for h
+	third, err := other.SubscribeWith(c, WithCloseOnCancel())
+	require.NoError(err)
+	assert.Len(other.(*hub).channelRefs.counts, 1)
+
+	first.Cancel()
+	first.Cancel()
+	assert.NotPanics(func() { h.Publish(1) })
+
+	h.Close()
+	assert.False(second.Active())
+	assert.Equal(1, <-c)
+	_, ok := <-c
+	assert.False(ok)
+
+	// the count for a closed channel is still released normally
+	third.Cancel()
+	assert.Empty(other.(*hub).channelRefs.counts)
+}
+
+func TestSelfCancel(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []TestEvent
+		h        = New()
+	)
+
+	_, err := h.Subscribe(func(e TestEvent, cancel Cancel) {
+		received = append(received, e)
+		if e.Message == "stop" {
+			cancel()
+		}
+	})
+
+	require.NoError(err)
+
+	h.Publish(TestEvent{Value: 1})
+	h.Publish(TestEvent{Value: 2, Message: "stop"})
+	h.Publish(TestEvent{Value: 3})
+
+	assert.Equal([]TestEvent{{Value: 1}, {Value: 2, Message: "stop"}}, received)
+	assert.False(h.PublishOK(TestEvent{Value: 4}))
+}
+
+func TestSubscriptionSetEnabled(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []int
+		h        = New()
+	)
+
+	sub, err := h.SubscribeWith(func(e int) { received = append(received, e) })
+	require.NoError(err)
+	assert.True(sub.Enabled())
+
+	h.Publish(1)
+	sub.SetEnabled(false)
+	assert.False(sub.Enabled())
+	assert.True(sub.Active())
+
+	// a disabled subscription is skipped, but still registered
+	assert.True(h.PublishOK(2))
+
+	sub.SetEnabled(true)
+	h.Publish(3)
+	assert.Equal([]int{1, 3}, received)
+}
+
+func TestWithInterceptor(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		validated []int
+		received  []int
+		all       []interface{}
+		h         = New()
+	)
+
+	_, err := h.SubscribeWith(
+		func(e int) { validated = append(validated, e) },
+		WithInterceptor(func(e interface{}) bool { return e.(int) >= 0 }),
+	)
+
+	require.NoError(err)
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+	Must(h.SubscribeAll(func(e interface{}) { all = append(all, e) }))
+
+	h.Publish(1)
+	h.Publish(-1)
+
+	// the veto stops the rest of the bucket, but not catch-all listeners
+	assert.Equal([]int{1}, validated)
+	assert.Equal([]int{1}, received)
+	assert.Equal([]interface{}{1, -1}, all)
+
+	// interceptors work under every panic policy
+	h = New(WithPanicPolicy(Recover))
+	received = nil
+	_, err = h.SubscribeWith(func(int) {}, WithInterceptor(func(interface{}) bool { return false }))
+	require.NoError(err)
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+
+	h.Publish(1)
+	assert.Empty(received)
+}
+
+func TestWithSkip(t *testing.T) {
+	const (
+		publishers = 8
+		events     = 100
+		skip       = 250
+	)
+
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		delivered uint64
+		h         = New()
+		wg        sync.WaitGroup
+	)
+
+	_, err := h.SubscribeWith(func(int) { atomic.AddUint64(&delivered, 1) }, WithSkip(skip))
+	require.NoError(err)
+
+	// concurrent publishes must skip exactly the requested number of events
+	for p := 0; p < publishers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < events; i++ {
+				h.Publish(i)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(uint64(publishers*events-skip), atomic.LoadUint64(&delivered))
+
+	// skipped events still count as handled
+	h = New()
+	_, err = h.SubscribeWith(func(int) {}, WithSkip(1))
+	require.NoError(err)
+	assert.True(h.PublishOK(1))
+}
+
+func TestWithLimit(t *testing.T) {
+	const (
+		publishers = 8
+		events     = 100
+		limit      = 250
+	)
+
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		delivered   uint64
+		afterCancel uint64
+		h           = New()
+		wg          sync.WaitGroup
+	)
+
+	sub, err := h.SubscribeWith(
+		func(int) { atomic.AddUint64(&delivered, 1) },
+		WithLimit(limit),
+		WithAfterCancel(func() { atomic.AddUint64(&afterCancel, 1) }),
+	)
+
+	require.NoError(err)
+
+	// concurrent publishes must never exceed the limit
+	for p := 0; p < publishers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < events; i++ {
+				h.Publish(i)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(uint64(limit), atomic.LoadUint64(&delivered))
+	assert.Equal(uint64(1), atomic.LoadUint64(&afterCancel))
+	assert.False(sub.Active())
+
+	// skip and limit compose
+	var received []int
+	sub, err = h.SubscribeWith(func(e int) { received = append(received, e) }, WithSkip(2), WithLimit(3))
+	require.NoError(err)
+	for i := 1; i <= 10; i++ {
+		h.Publish(i)
+	}
+
+	assert.Equal([]int{3, 4, 5}, received)
+	assert.False(sub.Active())
+
+	// the subscription can still be cancelled early
+	received = nil
+	sub, err = h.SubscribeWith(func(e int) { received = append(received, e) }, WithLimit(5))
+	require.NoError(err)
+	h.Publish(1)
+	assert.True(sub.Cancel())
+	h.Publish(2)
+	assert.Equal([]int{1}, received)
+
+	// a panicking listener still cancels once its limit is reached
+	h = New(WithPanicPolicy(Propagate))
+	sub, err = h.SubscribeWith(func(int) { panic("expected") }, WithLimit(1))
+	require.NoError(err)
+	assert.Panics(func() { h.Publish(1) })
+	assert.False(sub.Active())
+}
+
+func TestSubscriptionEventTypeName(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		h = New()
+	)
+
+	testData := []struct {
+		listener interface{}
+		expected string
+	}{
+		{func(TestEvent) {}, "github.com/johnabass/hub.TestEvent"},
+		{func(*TestEvent) {}, "*github.com/johnabass/hub.TestEvent"},
+		{func(int) {}, "int"},
+		{func([]TestEvent) {}, "[]hub.TestEvent"},
+	}
+
+	for _, record := range testData {
+		sub, err := h.SubscribeWith(record.listener)
+		require.NoError(err)
+		assert.Equal(record.expected, sub.EventTypeName())
+	}
+}
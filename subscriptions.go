@@ -1,59 +1,122 @@
 package hub
 
-import "reflect"
+import "sync/atomic"
 
-// subscriptions keeps track of sinks associated with a particular type of event.
-// this type follows copy-on-write semantics.
-type subscriptions map[reflect.Type][]sink
+// bucket holds the subscriptions for a single event type.  the []*Subscription value it holds is
+// immutable once stored, since concurrent publishes may still be iterating over it.  updates always
+// store a new slice, and must only be made while holding the hub's subscribeLock.  as a safeguard,
+// every stored slice has a capacity equal to its length, so that even an accidental append allocates
+// a new backing array.
+type bucket struct {
+	subs atomic.Value
+}
 
-// add makes a clone of this subscriptions instance with the given type mapped to a
-// new sink.
-func (s subscriptions) add(eventType reflect.Type, newSink sink) subscriptions {
-	clone := make(subscriptions)
-	for k, v := range s {
-		clone[k] = append([]sink{}, v...)
-	}
+func newBucket(subs []*Subscription) *bucket {
+	b := new(bucket)
+	b.store(subs)
+	return b
+}
 
-	clone[eventType] = append(clone[eventType], newSink)
-	return clone
+// load returns the current subscriptions in this bucket
+func (b *bucket) load() []*Subscription {
+	subs, _ := b.subs.Load().([]*Subscription)
+	return subs
 }
 
-// remove makes a clone of this subscriptions instance with the given event type's sink
-// removed.  if the tuple of eventType and oldSink do not exist in this subscriptions,
-// this instance is returned without modification.
-func (s subscriptions) remove(eventType reflect.Type, oldSink sink) subscriptions {
-	existing, ok := s[eventType]
-	if !ok {
-		return s
+func (b *bucket) store(subs []*Subscription) {
+	b.subs.Store(subs[:len(subs):len(subs)])
+}
+
+// add stores a copy of this bucket's subscriptions with sub inserted after every subscription whose rank
+// is not greater than its own.  ranks are only nonzero under WithSinkKindOrder, so sub is normally appended.
+func (b *bucket) add(sub *Subscription) {
+	existing := b.load()
+	i := len(existing)
+	for i > 0 && existing[i-1].rank > sub.rank {
+		i--
 	}
 
-	var updated []sink
+	updated := make([]*Subscription, 0, len(existing)+1)
+	updated = append(updated, existing[:i]...)
+	updated = append(updated, sub)
+	b.store(append(updated, existing[i:]...))
+}
+
+// remove stores a copy of this bucket's subscriptions without sub, which is matched by id rather
+// than by pointer.  the returned flag indicates whether sub was present.
+func (b *bucket) remove(sub *Subscription) bool {
+	existing := b.load()
+	updated := make([]*Subscription, 0, len(existing))
 	for _, candidate := range existing {
-		if candidate != oldSink {
+		if candidate.id != sub.id {
 			updated = append(updated, candidate)
 		}
 	}
 
 	if len(existing) == len(updated) {
-		return s
+		return false
 	}
 
-	clone := make(subscriptions)
+	b.store(updated)
+	return true
+}
+
+// subscriptions keeps track of sinks associated with a particular route.  a route is normally the
+// reflect.Type of an event, but may also be a keyedRoute when a hub uses WithKeyFunc.
+//
+// the map itself follows copy-on-write semantics, and is cloned only when an event type without
+// a bucket is added.  changes to an existing event type are made by storing a new slice into its
+// bucket, so that Subscribe and Cancel for that type cost O(bucket) rather than O(event types).
+// buckets that become empty are kept until the next clone, which discards them.
+type subscriptions map[interface{}]*bucket
+
+// clone makes a shallow copy of this subscriptions instance, omitting any empty buckets.
+// buckets are shared with the clone.
+func (s subscriptions) clone(capacity int) subscriptions {
+	clone := make(subscriptions, capacity)
 	for k, v := range s {
-		if k != eventType {
-			clone[k] = append([]sink{}, v...)
-		} else {
-			clone[k] = updated
+		if len(v.load()) > 0 {
+			clone[k] = v
 		}
 	}
 
 	return clone
 }
 
-// publish broadcasts the given event to the appropriate sinks
-func (s subscriptions) publish(e interface{}) {
-	v := reflect.ValueOf(e)
-	for _, sink := range s[reflect.TypeOf(e)] {
-		sink.send(v)
+// add appends the given subscription to its route's bucket, returning the updated subscriptions.
+// only when the route has no bucket is this instance cloned; otherwise, this instance is returned.
+// a clone is sized for at least capacity routes, which is the hint set by WithInitialBuckets.
+func (s subscriptions) add(sub *Subscription, capacity int) subscriptions {
+	if b, ok := s[sub.route]; ok {
+		b.add(sub)
+		return s
+	}
+
+	if capacity < len(s)+1 {
+		capacity = len(s) + 1
 	}
+
+	clone := s.clone(capacity)
+	clone[sub.route] = newBucket([]*Subscription{sub})
+	return clone
+}
+
+// remove removes the given subscription from its route's bucket.  subscriptions are matched by id
+// rather than by pointer.  this instance is always returned, and the returned flag indicates whether the
+// subscription was present.  only the bucket for the subscription's route is copied.
+func (s subscriptions) remove(sub *Subscription) (subscriptions, bool) {
+	if b, ok := s[sub.route]; ok {
+		return s, b.remove(sub)
+	}
+
+	return s, false
+}
+
+// sinks returns the subscriptions for the given route, which is typically an event type
+func (s subscriptions) sinks(route interface{}) []*Subscription {
+	if b, ok := s[route]; ok {
+		return b.load()
+	}
+
+	return nil
 }
@@ -0,0 +1,170 @@
+package hub
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptions(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		intType    = reflect.TypeOf(0)
+		stringType = reflect.TypeOf("")
+
+		s1 = &Subscription{id: 1, eventType: intType, route: intType}
+		s2 = &Subscription{id: 2, eventType: intType, route: intType}
+		s3 = &Subscription{id: 3, eventType: stringType, route: stringType}
+
+		empty subscriptions
+		first = empty.add(s1, 0)
+	)
+
+	assert.Empty(empty)
+	assert.Equal([]*Subscription{s1}, first.sinks(intType))
+
+	// adding to an existing event type updates its bucket without cloning
+	second := first.add(s2, 0)
+	assert.True(reflect.ValueOf(first).Pointer() == reflect.ValueOf(second).Pointer())
+	assert.Equal([]*Subscription{s1, s2}, second.sinks(intType))
+
+	// a new event type clones, sharing the unchanged bucket
+	third := second.add(s3, 0)
+	assert.Equal([]*Subscription{s3}, third.sinks(stringType))
+	assert.Nil(second.sinks(stringType))
+	assert.True(second[intType] == third[intType])
+
+	// a loaded bucket is never modified in place
+	loaded := third.sinks(intType)
+
+	// removal matches by id, so a rebuilt handle for the same subscription still works
+	removed, ok := third.remove(&Subscription{id: s1.id, eventType: intType, route: intType})
+	assert.True(ok)
+	assert.Equal([]*Subscription{s2}, removed.sinks(intType))
+	assert.Equal([]*Subscription{s1, s2}, loaded)
+
+	removed, ok = removed.remove(s2)
+	assert.True(ok)
+	assert.Empty(removed.sinks(intType))
+	assert.Equal([]*Subscription{s3}, removed.sinks(stringType))
+
+	// removing a subscription that isn't present returns the same instance
+	unchanged, ok := removed.remove(s1)
+	assert.False(ok)
+	assert.Equal(removed, unchanged)
+
+	// the empty bucket is discarded by the next clone
+	pruned := removed.add(&Subscription{id: 4, eventType: reflect.TypeOf(0.0), route: reflect.TypeOf(0.0)}, 0)
+	assert.NotContains(pruned, intType)
+	assert.Contains(pruned, stringType)
+}
+
+func TestSubscriptionsBucketCapacity(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		intType = reflect.TypeOf(0)
+		subs    []*Subscription
+		current subscriptions
+	)
+
+	for i := 0; i < 5; i++ {
+		sub := &Subscription{id: uint64(i + 1), eventType: intType, route: intType}
+		subs = append(subs, sub)
+		current = current.add(sub, 0)
+
+		bucket := current.sinks(intType)
+		assert.Equal(len(bucket), cap(bucket))
+	}
+
+	for _, sub := range subs[:4] {
+		current, _ = current.remove(sub)
+
+		bucket := current.sinks(intType)
+		assert.Equal(len(bucket), cap(bucket))
+	}
+}
+
+func TestSubscriptionsConcurrentChurn(t *testing.T) {
+	const (
+		publishers  = 4
+		subscribers = 4
+		iterations  = 200
+	)
+
+	var (
+		h    = New()
+		stop = make(chan struct{})
+
+		publishing  sync.WaitGroup
+		subscribing sync.WaitGroup
+	)
+
+	// a long-lived listener that verifies every event it receives
+	Must(h.Subscribe(func(e TestEvent) {
+		if e.Message != strconv.Itoa(e.Value) {
+			t.Errorf("corrupted event: %v", e)
+		}
+	}))
+
+	for p := 0; p < publishers; p++ {
+		publishing.Add(1)
+		go func() {
+			defer publishing.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					h.Publish(TestEvent{Value: i, Message: strconv.Itoa(i)})
+				}
+			}
+		}()
+	}
+
+	for s := 0; s < subscribers; s++ {
+		subscribing.Add(1)
+		go func() {
+			defer subscribing.Done()
+			for i := 0; i < iterations; i++ {
+				c1 := Must(h.Subscribe(func(TestEvent) {}))
+				c2 := Must(h.Subscribe(func(string) {}))
+				c1()
+				c2()
+			}
+		}()
+	}
+
+	subscribing.Wait()
+	close(stop)
+	publishing.Wait()
+}
+
+// BenchmarkCancel measures the cost of cancelling a subscription as the number of distinct event types grows.
+// Since only the cancelled subscription's bucket is copied, the cost should remain flat.
+func BenchmarkCancel(b *testing.B) {
+	intType := reflect.TypeOf(0)
+	for _, eventTypes := range []int{10, 100, 1000, 10000} {
+		b.Run(strconv.Itoa(eventTypes), func(b *testing.B) {
+			h := New().(*hub)
+			for i := 0; i < eventTypes; i++ {
+				h.register(reflect.ArrayOf(i, intType), &sinkFunc{}, nil)
+			}
+
+			// a long-lived subscription keeps the bucket in place between iterations
+			h.register(intType, &sinkFunc{}, nil)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				sub, _ := h.register(intType, &sinkFunc{}, nil)
+				b.StartTimer()
+
+				sub.Cancel()
+			}
+		})
+	}
+}
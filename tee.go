@@ -0,0 +1,23 @@
+package hub
+
+func (h *hub) Tee(dst Publisher) Cancel {
+	return h.tee(dst)
+}
+
+func (h *hub) TeeWithReplay(dst Publisher) Cancel {
+	return h.tee(dst, func(s *Subscription) { s.stickyAll = true })
+}
+
+// tee implements both Tee and TeeWithReplay
+func (h *hub) tee(dst Publisher, options ...SubscribeOption) Cancel {
+	if dst == nil || dst == Publisher(h) {
+		return func() {}
+	}
+
+	sub, err := h.register(anyType, &sinkAll{f: dst.Publish}, listenerKey(dst.Publish), options...)
+	if err != nil {
+		return func() {}
+	}
+
+	return sub.cancelFunc()
+}
@@ -0,0 +1,74 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTee(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		src = New()
+		dst = New()
+
+		received []interface{}
+	)
+
+	Must(dst.SubscribeAll(func(e interface{}) { received = append(received, e) }))
+
+	cancel := src.Tee(dst)
+	assert.NotNil(cancel)
+
+	src.Publish(1)
+	src.Publish("two")
+	cancel()
+	src.Publish(3.0)
+
+	assert.Equal([]interface{}{1, "two"}, received)
+
+	// self-tees and nil destinations are ignored
+	assert.NotPanics(func() {
+		src.Tee(src)()
+		src.Tee(nil)()
+		src.Publish(4)
+	})
+}
+
+func TestTeeWithReplay(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		src = New()
+		dst = New()
+
+		received []interface{}
+	)
+
+	Must(dst.SubscribeAll(func(e interface{}) { received = append(received, e) }))
+
+	src.PublishSticky(1)
+	src.PublishSticky(2)
+	src.PublishSticky("sticky")
+	src.Publish(3.0)
+
+	cancel := src.TeeWithReplay(dst)
+	assert.ElementsMatch([]interface{}{2, "sticky"}, received)
+
+	src.Publish(4)
+	assert.Equal(4, received[len(received)-1])
+	cancel()
+	src.PublishSticky(5)
+	assert.Len(received, 3)
+
+	// ordinary catch-all subscriptions still do not receive sticky events
+	var all []interface{}
+	Must(src.SubscribeAll(func(e interface{}) { all = append(all, e) }))
+	assert.Empty(all)
+
+	assert.NotPanics(func() {
+		src.TeeWithReplay(src)()
+		src.TeeWithReplay(nil)()
+	})
+}
@@ -0,0 +1,87 @@
+package hub
+
+import (
+	"context"
+	"reflect"
+)
+
+// Span is a single traced operation started by a Tracer
+type Span interface {
+	// End completes this span.  The time between starting and ending a span is its duration.
+	End()
+}
+
+// Tracer starts spans for the events delivered by a hub.  It is deliberately minimal, so that any tracing
+// backend, such as OpenTelemetry, can be adapted with a few lines of code.  StartSpan returns the span along
+// with a context that carries it, which becomes the parent of any spans started with that context.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+const (
+	// PublishSpanName is the name of the span that covers the delivery of an event to all of its sinks
+	PublishSpanName = "hub.publish"
+
+	// DeliverSpanName is the name of the span that covers the delivery of an event to a single sink
+	DeliverSpanName = "hub.deliver"
+
+	// EventTypeAttribute is the span attribute holding the fully qualified name of an event's type
+	EventTypeAttribute = "hub.event.type"
+
+	// SinkKindAttribute is the span attribute holding the kind of sink an event is delivered to
+	SinkKindAttribute = "hub.sink.kind"
+
+	// LabelAttribute is the span attribute holding the label of the subscription an event is delivered to,
+	// if the subscription has a label
+	LabelAttribute = "hub.label"
+)
+
+// WithTracer traces each event published to a hub.  The delivery of an event starts a PublishSpanName span,
+// and each delivery to a sink starts a DeliverSpanName child of that span, so that per-listener latency can
+// be seen alongside the event as a whole.  Spans start with the context that accompanies the event, which
+// allows a trace to continue from PublishContext, and context-aware listeners receive the context of their
+// own span.  The publish span starts after any middleware has run.
+//
+// When no tracer is set, which is the default, tracing has no cost.
+func WithTracer(t Tracer) Option {
+	return func(h *hub) {
+		h.tracer = t
+	}
+}
+
+// eventTypeName returns the name of an event type for tracing.  a nil event has no type.
+func eventTypeName(eventType reflect.Type) string {
+	if eventType == nil {
+		return "nil"
+	}
+
+	return typeName(eventType)
+}
+
+// tracePublish starts the span for a message's delivery to all of its sinks.  it must only be called
+// when this hub has a tracer.
+func (h *hub) tracePublish(eventType reflect.Type, m *message) Span {
+	var span Span
+	m.ctx, span = h.tracer.StartSpan(m.context(), PublishSpanName, map[string]string{
+		EventTypeAttribute: eventTypeName(eventType),
+	})
+
+	return span
+}
+
+// traceDeliver starts the span for a message's delivery to a single subscription.  it must only be
+// called when this hub has a tracer.
+func (h *hub) traceDeliver(s *Subscription, m *message) Span {
+	attributes := map[string]string{
+		EventTypeAttribute: eventTypeName(reflect.TypeOf(m.event())),
+		SinkKindAttribute:  kindOf(s.sink).String(),
+	}
+
+	if len(s.label) > 0 {
+		attributes[LabelAttribute] = s.label
+	}
+
+	var span Span
+	m.ctx, span = h.tracer.StartSpan(m.context(), DeliverSpanName, attributes)
+	return span
+}
@@ -0,0 +1,111 @@
+package hub
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name       string
+	attributes map[string]string
+	parent     *testSpan
+	ended      bool
+}
+
+func (ts *testSpan) End() {
+	ts.ended = true
+}
+
+type testTracer struct {
+	lock  sync.Mutex
+	spans []*testSpan
+}
+
+func (tt *testTracer) StartSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	span := &testSpan{name: name, attributes: attributes}
+	span.parent, _ = ctx.Value(testSpanKey{}).(*testSpan)
+
+	tt.lock.Lock()
+	tt.spans = append(tt.spans, span)
+	tt.lock.Unlock()
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func testTracerSpans(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		tracer   = new(testTracer)
+		h        = New(WithTracer(tracer))
+		received *testSpan
+	)
+
+	sub, err := h.SubscribeWith(func(TestEvent) {}, WithLabel("first"))
+	require.NoError(err)
+	require.NotNil(sub)
+
+	_, err = h.SubscribeContextHandler(func(ctx context.Context, e TestEvent) {
+		received, _ = ctx.Value(testSpanKey{}).(*testSpan)
+	})
+
+	require.NoError(err)
+
+	root := &testSpan{name: "root"}
+	h.PublishContext(context.WithValue(context.Background(), testSpanKey{}, root), TestEvent{Value: 1})
+
+	require.Len(tracer.spans, 3)
+	publish, first, second := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+
+	assert.Equal(PublishSpanName, publish.name)
+	assert.Equal(map[string]string{EventTypeAttribute: "github.com/johnabass/hub.TestEvent"}, publish.attributes)
+	assert.Equal(root, publish.parent)
+
+	assert.Equal(DeliverSpanName, first.name)
+	assert.Equal(
+		map[string]string{
+			EventTypeAttribute: "github.com/johnabass/hub.TestEvent",
+			SinkKindAttribute:  "func",
+			LabelAttribute:     "first",
+		},
+		first.attributes,
+	)
+
+	assert.Equal(publish, first.parent)
+	assert.Equal(publish, second.parent)
+	assert.NotContains(second.attributes, LabelAttribute)
+
+	// context-aware listeners receive the context of their own delivery span
+	assert.Equal(second, received)
+
+	for _, span := range tracer.spans {
+		assert.True(span.ended)
+	}
+}
+
+func testTracerNoMatch(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		tracer = new(testTracer)
+		h      = New(WithTracer(tracer))
+	)
+
+	h.Publish("unmatched")
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(PublishSpanName, tracer.spans[0].name)
+	assert.Equal("string", tracer.spans[0].attributes[EventTypeAttribute])
+	assert.True(tracer.spans[0].ended)
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("Spans", testTracerSpans)
+	t.Run("NoMatch", testTracerNoMatch)
+}
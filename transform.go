@@ -0,0 +1,49 @@
+package hub
+
+import "reflect"
+
+// sinkTransform is a sink that maps events into other events, then publishes them
+type sinkTransform struct {
+	hub  *hub
+	from reflect.Type
+	fn   func(interface{}) interface{}
+}
+
+func (st *sinkTransform) send(m message) {
+	for _, t := range m.transformed {
+		if t == st.from {
+			// this transform has already been applied, so this is a cycle
+			return
+		}
+	}
+
+	result := st.fn(m.value.Interface())
+	if result == nil {
+		return
+	}
+
+	transformed := make([]reflect.Type, len(m.transformed), len(m.transformed)+1)
+	copy(transformed, m.transformed)
+
+	st.hub.dispatch(
+		reflect.TypeOf(result),
+		message{
+			value:       reflect.ValueOf(result),
+			meta:        m.meta,
+			transformed: append(transformed, st.from),
+		},
+		nil,
+	)
+}
+
+func (h *hub) Transform(from reflect.Type, fn func(interface{}) interface{}) (Cancel, error) {
+	if from == nil || from.Kind() == reflect.Interface {
+		return nil, ErrInvalidEventType
+	}
+
+	if fn == nil {
+		return nil, ErrInvalidListener
+	}
+
+	return h.registerCancel(from, &sinkTransform{hub: h, from: from, fn: fn}, nil)
+}
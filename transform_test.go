@@ -0,0 +1,70 @@
+package hub
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		events  []TestEvent
+		strings []string
+		metas   []Meta
+		h       = New()
+	)
+
+	Must(h.Subscribe(func(e TestEvent, meta Meta) {
+		events = append(events, e)
+		metas = append(metas, meta)
+	}))
+
+	Must(h.Subscribe(func(s string) { strings = append(strings, s) }))
+
+	// string -> TestEvent
+	cancel, err := h.Transform(reflect.TypeOf(""), func(e interface{}) interface{} {
+		v, err := strconv.Atoi(e.(string))
+		if err != nil {
+			return nil
+		}
+
+		return TestEvent{Value: v, Message: e.(string)}
+	})
+
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	// TestEvent -> string, which forms a cycle
+	_, err = h.Transform(reflect.TypeOf(TestEvent{}), func(e interface{}) interface{} {
+		return strconv.Itoa(e.(TestEvent).Value + 1)
+	})
+
+	require.NoError(err)
+
+	h.PublishMeta("1", Meta{CorrelationID: "abc"})
+	assert.Equal([]string{"1", "2"}, strings)
+	assert.Equal([]TestEvent{{Value: 1, Message: "1"}}, events)
+	assert.Equal("abc", metas[0].CorrelationID)
+
+	// a nil result publishes nothing
+	h.Publish("not a number")
+	assert.Len(events, 1)
+
+	cancel()
+	h.Publish("3")
+	assert.Len(events, 1)
+
+	cancel, err = h.Transform(nil, func(e interface{}) interface{} { return e })
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	cancel, err = h.Transform(reflect.TypeOf(0), nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
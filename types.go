@@ -0,0 +1,86 @@
+package hub
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+func (h *hub) SubscribeTypes(l func(interface{}), types []reflect.Type, afterCancel ...func()) (Cancel, error) {
+	if l == nil {
+		return nil, ErrInvalidListener
+	}
+
+	if len(types) == 0 {
+		return nil, ErrInvalidEventType
+	}
+
+	// validate everything up front, so that an invalid type registers nothing
+	var (
+		unique = make([]reflect.Type, 0, len(types))
+		seen   = make(map[reflect.Type]bool, len(types))
+	)
+
+	for _, eventType := range types {
+		if eventType == nil {
+			return nil, ErrInvalidEventType
+		} else if err := h.checkEventType(eventType); err != nil {
+			return nil, err
+		}
+
+		if !seen[eventType] {
+			seen[eventType] = true
+			unique = append(unique, eventType)
+		}
+	}
+
+	// remaining counts the registrations that have yet to be cancelled, plus one that is held until every
+	// registration has been made.  afterCancel runs once, when the last registration is removed by any means.
+	var (
+		s         = &sinkAll{f: l}
+		key       = listenerKey(l)
+		subs      = make([]*Subscription, 0, len(unique))
+		remaining = int32(len(unique)) + 1
+	)
+
+	release := func() {
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			for _, f := range afterCancel {
+				f()
+			}
+		}
+	}
+
+	for _, eventType := range unique {
+		sub, err := h.register(eventType, s, key, WithAfterCancel(release))
+		if err != nil {
+			// the held count is never released, so afterCancel does not run for a failed subscribe
+			for _, registered := range subs {
+				registered.Cancel()
+			}
+
+			return nil, err
+		}
+
+		if sub.sink != sink(s) {
+			// an existing subscription was returned by WithDedup, and it remains owned by its original caller
+			atomic.AddInt32(&remaining, -1)
+			continue
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if len(subs) > 0 {
+		release()
+	}
+
+	return func() {
+		for _, sub := range subs {
+			sub.Cancel()
+		}
+	}, nil
+}
+
+func (h *hub) SubscribeFor(eventType reflect.Type, l func(interface{}), afterCancel ...func()) (Cancel, error) {
+	return h.SubscribeTypes(l, []reflect.Type{eventType}, afterCancel...)
+}
@@ -0,0 +1,33 @@
+package hub
+
+func (h *hub) Unsubscribe(l interface{}) (bool, error) {
+	eventType, _, err := newSink(l)
+	if err != nil {
+		return false, err
+	}
+
+	// func(interface{}) is the shape of a catch-all listener, so its route is the catch-all route
+	if eventType != anyType {
+		if err := h.checkEventType(eventType); err != nil {
+			return false, err
+		}
+	}
+
+	var (
+		key     = listenerKey(l)
+		matches []*Subscription
+	)
+
+	for _, candidate := range h.subscriptions.sinks(eventType) {
+		if sameKey(candidate.key, key) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	removed := false
+	for _, s := range matches {
+		removed = s.Cancel() || removed
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,77 @@
+package hub
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValidationError is the error passed to a hub's error handler when an event fails validation.  See RegisterValidator.
+type ValidationError struct {
+	// EventType is the type of the invalid event
+	EventType reflect.Type
+
+	// Event is the invalid event
+	Event interface{}
+
+	// Err is the error returned by the validator
+	Err error
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %v event: %v", ve.EventType, ve.Err)
+}
+
+// loadValidators returns the current validators, which may be nil
+func (h *hub) loadValidators() map[reflect.Type]func(interface{}) error {
+	validators, _ := h.validators.Load().(map[reflect.Type]func(interface{}) error)
+	return validators
+}
+
+func (h *hub) RegisterValidator(eventType reflect.Type, f func(interface{}) error) error {
+	if eventType == nil {
+		return ErrInvalidEventType
+	}
+
+	h.subscribeLock.Lock()
+	defer h.subscribeLock.Unlock()
+
+	// like aliases, validators are copy-on-write so that publishes need no lock
+	current := h.loadValidators()
+	updated := make(map[reflect.Type]func(interface{}) error, len(current)+1)
+	for k, v := range current {
+		updated[k] = v
+	}
+
+	if f != nil {
+		updated[eventType] = f
+	} else {
+		delete(updated, eventType)
+	}
+
+	h.validators.Store(updated)
+	return nil
+}
+
+// valid runs the validator for an event's type, if there is one.  an invalid event is reported to the
+// error handler, and this method returns false if it must not be delivered.
+func (h *hub) valid(eventType reflect.Type, m message) bool {
+	validate, ok := h.loadValidators()[eventType]
+	if !ok {
+		return true
+	}
+
+	e := m.event()
+	if err := validate(e); err != nil {
+		ve := &ValidationError{EventType: eventType, Event: e, Err: err}
+		if h.rejectInvalid && m.group != nil {
+			// PublishGroup returns the error for an event that was never delivered
+			m.group.fail(ve)
+			return false
+		}
+
+		h.handleError(ve)
+		return !h.rejectInvalid
+	}
+
+	return true
+}
@@ -0,0 +1,115 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNegative = errors.New("negative")
+
+func validatePositive(e interface{}) error {
+	if e.(int) < 0 {
+		return errNegative
+	}
+
+	return nil
+}
+
+func testRegisterValidatorDeliver(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received []int
+		errs     []error
+		h        = New(WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	)
+
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+	require.NoError(h.RegisterValidator(reflect.TypeOf(0), validatePositive))
+
+	h.Publish(1)
+	assert.True(h.PublishOK(-1))
+	assert.Equal([]int{1, -1}, received)
+	require.Len(errs, 1)
+
+	ve, ok := errs[0].(*ValidationError)
+	require.True(ok)
+	assert.Equal(reflect.TypeOf(0), ve.EventType)
+	assert.Equal(-1, ve.Event)
+	assert.Equal(errNegative, ve.Err)
+	assert.Equal("invalid int event: negative", ve.Error())
+
+	// a nil validator removes the existing one
+	require.NoError(h.RegisterValidator(reflect.TypeOf(0), nil))
+	h.Publish(-2)
+	assert.Len(errs, 1)
+
+	assert.Equal(ErrInvalidEventType, h.RegisterValidator(nil, validatePositive))
+}
+
+func testRegisterValidatorReject(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		received  []int
+		errs      []error
+		unhandled []interface{}
+
+		h = New(
+			WithRejectInvalid(),
+			WithErrorHandler(func(err error) { errs = append(errs, err) }),
+			WithUnhandled(func(e interface{}) { unhandled = append(unhandled, e) }),
+		)
+	)
+
+	Must(h.Subscribe(func(e int) { received = append(received, e) }))
+	require.NoError(h.RegisterValidator(reflect.TypeOf(0), validatePositive))
+
+	assert.True(h.PublishOK(1))
+	assert.False(h.PublishOK(-1))
+	assert.Equal([]int{1}, received)
+	assert.Len(errs, 1)
+	assert.Empty(unhandled)
+
+	// PublishGroup returns the validation error rather than passing it to the error handler
+	assert.NoError(h.PublishGroup(context.Background(), 2))
+	err := h.PublishGroup(context.Background(), -2)
+	require.IsType((*ValidationError)(nil), err)
+	assert.Equal(errNegative, err.(*ValidationError).Err)
+	assert.Equal([]int{1, 2}, received)
+	assert.Len(errs, 1)
+	assert.Empty(unhandled)
+}
+
+func testRegisterValidatorSticky(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		errs []error
+		late []int
+		h    = New(WithRejectInvalid(), WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	)
+
+	require.NoError(h.RegisterValidator(reflect.TypeOf(0), validatePositive))
+
+	// a rejected sticky event is neither delivered nor retained for later subscribers
+	h.PublishSticky(1)
+	h.PublishSticky(-1)
+	Must(h.Subscribe(func(e int) { late = append(late, e) }))
+	assert.Equal([]int{1}, late)
+	assert.Len(errs, 1)
+}
+
+func TestRegisterValidator(t *testing.T) {
+	t.Run("Deliver", testRegisterValidatorDeliver)
+	t.Run("Reject", testRegisterValidatorReject)
+	t.Run("Sticky", testRegisterValidatorSticky)
+}
@@ -0,0 +1,47 @@
+package hub
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// sinkWriter is a sink that writes newline-delimited JSON to an io.Writer
+type sinkWriter struct {
+	lock    sync.Mutex
+	w       io.Writer
+	onError func(error)
+}
+
+func (sw *sinkWriter) send(m message) {
+	data, err := json.Marshal(m.value.Interface())
+	if err != nil {
+		m.fail(Errored, err)
+		sw.onError(err)
+		return
+	}
+
+	data = append(data, '\n')
+
+	sw.lock.Lock()
+	_, err = sw.w.Write(data)
+	sw.lock.Unlock()
+
+	if err != nil {
+		m.fail(Errored, err)
+		sw.onError(err)
+	}
+}
+
+func (h *hub) SubscribeWriter(eventType reflect.Type, w io.Writer) (Cancel, error) {
+	if eventType == nil || eventType.Kind() == reflect.Interface {
+		return nil, ErrInvalidEventType
+	}
+
+	if w == nil {
+		return nil, ErrInvalidListener
+	}
+
+	return h.registerCancel(eventType, &sinkWriter{w: w, onError: h.handleError}, nil)
+}
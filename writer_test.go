@@ -0,0 +1,88 @@
+package hub
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (fw failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("expected")
+}
+
+func testSubscribeWriterSuccess(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output bytes.Buffer
+		h      = New()
+	)
+
+	cancel, err := h.SubscribeWriter(reflect.TypeOf(TestEvent{}), &output)
+	require.NoError(err)
+	require.NotNil(cancel)
+
+	h.Publish(TestEvent{Value: 1, Message: "first"})
+	h.Publish("not written")
+	h.Publish(TestEvent{Value: 2, Message: "second"})
+
+	cancel()
+	h.Publish(TestEvent{Value: 3, Message: "third"})
+
+	assert.Equal(
+		"{\"Value\":1,\"Message\":\"first\"}\n{\"Value\":2,\"Message\":\"second\"}\n",
+		output.String(),
+	)
+}
+
+func testSubscribeWriterErrors(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		errs []error
+		h    = New(WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	)
+
+	_, err := h.SubscribeWriter(reflect.TypeOf(func() {}), new(bytes.Buffer))
+	require.NoError(err)
+
+	_, err = h.SubscribeWriter(reflect.TypeOf(0), failingWriter{})
+	require.NoError(err)
+
+	h.Publish(func() {})
+	h.Publish(123)
+	assert.Len(errs, 2)
+}
+
+func testSubscribeWriterInvalid(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		h      = New()
+	)
+
+	cancel, err := h.SubscribeWriter(nil, new(bytes.Buffer))
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	cancel, err = h.SubscribeWriter(reflect.TypeOf((*error)(nil)).Elem(), new(bytes.Buffer))
+	assert.Equal(ErrInvalidEventType, err)
+	assert.Nil(cancel)
+
+	cancel, err = h.SubscribeWriter(reflect.TypeOf(0), nil)
+	assert.Equal(ErrInvalidListener, err)
+	assert.Nil(cancel)
+}
+
+func TestSubscribeWriter(t *testing.T) {
+	t.Run("Success", testSubscribeWriterSuccess)
+	t.Run("Errors", testSubscribeWriterErrors)
+	t.Run("Invalid", testSubscribeWriterInvalid)
+}